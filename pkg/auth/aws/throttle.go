@@ -0,0 +1,17 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// IsThrottled returns true if err was caused by AWS throttling the request
+//
+// Use this together with `composite.MarkThrottled` to slow down the requeue
+// rate of the function.
+func IsThrottled(err error) bool {
+	if err == nil {
+		return false
+	}
+	return retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary
+}
@@ -0,0 +1,59 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// testAPIError is an API error carrying an AWS error code
+type testAPIError struct {
+	code string
+}
+
+func (e *testAPIError) Error() string {
+	return "api error " + e.code
+}
+
+func (e *testAPIError) ErrorCode() string {
+	return e.code
+}
+
+func TestIsThrottled(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"Nil": {},
+		"Plain": {
+			err: errors.New("boom"),
+		},
+		"Throttling": {
+			err:  &testAPIError{code: "Throttling"},
+			want: true,
+		},
+		"ThrottlingException": {
+			err:  &testAPIError{code: "ThrottlingException"},
+			want: true,
+		},
+		"RequestLimitExceeded": {
+			err:  &testAPIError{code: "RequestLimitExceeded"},
+			want: true,
+		},
+		"Wrapped": {
+			err:  fmt.Errorf("cannot describe vpc: %w", &testAPIError{code: "Throttling"}),
+			want: true,
+		},
+		"OtherCode": {
+			err: &testAPIError{code: "AccessDenied"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := IsThrottled(tc.err); got != tc.want {
+				t.Errorf("IsThrottled(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
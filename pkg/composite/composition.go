@@ -2,23 +2,34 @@ package composite
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/function-sdk-go/logging"
 	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
 	"github.com/crossplane/function-sdk-go/request"
 	"github.com/crossplane/function-sdk-go/resource"
 	"github.com/crossplane/function-sdk-go/resource/composed"
 	"github.com/crossplane/function-sdk-go/response"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
-// Composition contains the main request objects required for interacting with
-// composition function resources.
-type Composition struct {
+// TypedComposition contains the main request objects required for interacting
+// with composition function resources.
+//
+// The type parameters describe the shape of the observed composite resource
+// and the function input so that both can be used without type assertions.
+//
+//   - `XR` The struct the observed composite resource is decoded into
+//   - `In` The function input type. This is normally a pointer to the input
+//     struct generated for your function
+type TypedComposition[XR any, In InputProvider] struct {
 	// ObservedComposite is an object that reflects the composite resource that
 	//is created from the claim
-	ObservedComposite any
+	ObservedComposite XR
 
 	// DesiredComposite is the raw composite resource we want creating
 	DesiredComposite *resource.Composite
@@ -31,18 +42,106 @@ type Composition struct {
 	DesiredComposed map[resource.Name]*resource.DesiredComposed
 
 	// Input is the information brought in from the function binding
-	Input InputProvider
+	Input In
+
+	// observed is the raw observed composite resource
+	observed *resource.Composite
+
+	// context is the pipeline context handed between functions
+	context *structpb.Struct
+
+	// extra holds the extra resources supplied by Crossplane
+	extra map[string][]resource.Extra
+
+	// requirements holds the extra resources requested from Crossplane
+	requirements map[string]*fnv1.ResourceSelector
+
+	// results holds the results to be emitted by ToResponse
+	results []Result
+
+	// conditions holds the conditions to be emitted by ToResponse
+	conditions []Condition
+
+	// ttlPolicy decides the TTL of the response
+	ttlPolicy TTLPolicy
+
+	// throttled is set when an upstream API throttled the function
+	throttled bool
+
+	// propagation lists the labels and annotations copied onto composed
+	// resources
+	propagation *Propagation
+
+	// upstream holds the names of desired composed resources produced by
+	// earlier steps in the pipeline
+	upstream map[resource.Name]struct{}
+
+	// generated holds the names of desired composed resources added during
+	// this run
+	generated map[resource.Name]struct{}
+
+	// usages holds the Usages declared with Uses
+	usages []usage
+
+	// dependencies holds the dependencies of desired composed resources
+	// added with DependsOn
+	dependencies map[resource.Name][]dependency
+
+	// gated holds the resources held back by ToResponse
+	gated []GatedResource
+
+	// deletionOrder lists the composed resources to drop in order while the
+	// composite resource is deleted
+	deletionOrder []string
 }
 
+// Composition is the untyped form of TypedComposition.
+//
+// ObservedComposite holds whatever was handed to `New` and Input is only known
+// to be a `runtime.Object`.
+type Composition = TypedComposition[any, InputProvider]
+
 // InputProvider This is basically a wrapper to `runtime.Object` and exists to
 // ensure that all inputs to the `New` conform to a supported type
 type InputProvider interface {
 	runtime.Object
 }
 
+// Option configures how `New` and `NewTyped` read the request
+type Option func(*options)
+
+type options struct {
+	inputSchema   *InputSchema
+	inputRegistry *InputRegistry
+	log           logging.Logger
+	waitingTTL    time.Duration
+	deletionOrder []string
+}
+
+// newOptions applies opts to the default options
+func newOptions(opts ...Option) *options {
+	o := &options{
+		waitingTTL: WaitingTTL,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithLogger logs what happens while the request is read, for example the
+// conversion of a deprecated input version
+func WithLogger(log logging.Logger) Option {
+	return func(o *options) {
+		o.log = log
+	}
+}
+
 // New takes a RunFunctionRequest object and converts it to a Composition
 //
-// This method should be called at the top of your RunFunction.
+// This method should be called at the top of your RunFunction. Options such as
+// `WithInputSchema` configure how the request is read. `Run` wraps the
+// boilerplate shown below.
 //
 // Example:
 //
@@ -68,55 +167,109 @@ type InputProvider interface {
 //		response.Normal(rsp, "Successful run")
 //		return rsp, nil
 //	}
-func New(req *fnv1.RunFunctionRequest, input InputProvider, composite any) (c *Composition, err error) {
+func New(req *fnv1.RunFunctionRequest, input InputProvider, composite any, opts ...Option) (c *Composition, err error) {
 	c = &Composition{
 		Input:             input,
 		ObservedComposite: composite,
 	}
+	err = c.load(req, opts...)
+	return
+}
 
+// NewTyped takes a RunFunctionRequest object and converts it to a
+// TypedComposition
+//
+// This is the type safe counterpart of `New`. The observed composite resource
+// is decoded into a new `XR` and the function input is read into `input`.
+//
+// Example:
+//
+//	input := v1beta1.Input{}
+//	composed, err := composite.NewTyped[v1beta1.XCluster](req, &input)
+//	if err != nil {
+//		response.Fatal(rsp, errors.Wrap(err, "error setting up function "+composedName))
+//		return rsp, nil
+//	}
+//
+//	region := composed.ObservedComposite.Spec.Region
+func NewTyped[XR any, In InputProvider](req *fnv1.RunFunctionRequest, input In, opts ...Option) (c *TypedComposition[XR, In], err error) {
+	c = &TypedComposition[XR, In]{
+		Input: input,
+	}
+	err = c.load(req, opts...)
+	return
+}
+
+// load reads the observed and desired state from the request into the
+// composition
+func (c *TypedComposition[XR, In]) load(req *fnv1.RunFunctionRequest, opts ...Option) (err error) {
+	o := newOptions(opts...)
+	c.deletionOrder = o.deletionOrder
+
+	c.context = &structpb.Struct{Fields: make(map[string]*structpb.Value)}
+	if req.GetContext() != nil {
+		c.context = proto.Clone(req.GetContext()).(*structpb.Struct)
+	}
+
+	// Parts of the request are read independently so that every failure is
+	// reported at once
+	var errs AggregateError
 	if c.DesiredComposite, err = request.GetDesiredCompositeResource(req); err != nil {
-		err = errors.Wrapf(err, "cannot get desired composed resources from %T", req)
-		return
+		errs.Append(errors.Wrapf(err, "cannot get desired composed resources from %T", req))
 	}
 
 	var oxr *resource.Composite
 	if oxr, err = request.GetObservedCompositeResource(req); err != nil {
-		err = errors.Wrap(err, "cannot get observed composite resource")
-		return
-	}
+		errs.Append(errors.Wrap(err, "cannot get observed composite resource"))
+	} else {
+		c.observed = oxr
 
-	if err = To(oxr.Resource.Object, &c.ObservedComposite); err != nil {
-		err = errors.Wrapf(err, "Failed to convert XR object to struct %T", c.ObservedComposite)
-		return
-	}
+		if err = To(oxr.Resource.Object, &c.ObservedComposite); err != nil {
+			errs.Append(errors.Wrapf(err, "Failed to convert XR object to struct %T", c.ObservedComposite))
+		}
 
-	c.DesiredComposite.Resource.SetAPIVersion(oxr.Resource.GetAPIVersion())
-	c.DesiredComposite.Resource.SetKind(oxr.Resource.GetKind())
+		if c.DesiredComposite != nil {
+			c.DesiredComposite.Resource.SetAPIVersion(oxr.Resource.GetAPIVersion())
+			c.DesiredComposite.Resource.SetKind(oxr.Resource.GetKind())
+		}
+	}
 
 	if c.DesiredComposed, err = request.GetDesiredComposedResources(req); err != nil {
-		err = errors.Wrapf(err, "cannot get desired composite resources from %T", req)
-		return
+		errs.Append(errors.Wrapf(err, "cannot get desired composite resources from %T", req))
+	}
+
+	c.upstream = make(map[resource.Name]struct{}, len(c.DesiredComposed))
+	for n := range c.DesiredComposed {
+		c.upstream[n] = struct{}{}
 	}
 
 	if c.ObservedComposed, err = request.GetObservedComposedResources(req); err != nil {
-		err = errors.Wrapf(err, "cannot get observed composed resources from %T", req)
-		return
+		errs.Append(errors.Wrapf(err, "cannot get observed composed resources from %T", req))
 	}
 
-	if err = request.GetInput(req, c.Input); err != nil {
-		return
+	if c.extra, err = request.GetExtraResources(req); err != nil {
+		errs.Append(errors.Wrapf(err, "cannot get extra resources from %T", req))
 	}
 
-	return
+	errs.Append(c.readInput(req, o))
+	return errs.ErrorOrNil()
 }
 
 // ToResponse converts the composition back into the response object
 //
 // This method should be called at the end of your RunFunction immediately
-// before returning a normal response.
+// before returning a normal response. Resources whose dependencies are not
+// satisfied are held back first, see `Gated`. Usages declared with `Uses` are
+// then added to the desired resources, or pruned.
 //
 // Wrap this in an error handler and set `response.Fatal` on error
-func (c *Composition) ToResponse(r *fnv1.RunFunctionResponse) (err error) {
+func (c *TypedComposition[XR, In]) ToResponse(r *fnv1.RunFunctionResponse) (err error) {
+	c.gate()
+
+	if err = c.setUsages(); err != nil {
+		return
+	}
+
 	if err = response.SetDesiredCompositeResource(r, c.DesiredComposite); err != nil {
 		err = errors.Wrapf(err, "cannot set desired composite resources in %T", r)
 		return
@@ -124,31 +277,134 @@ func (c *Composition) ToResponse(r *fnv1.RunFunctionResponse) (err error) {
 
 	if err = response.SetDesiredComposedResources(r, c.DesiredComposed); err != nil {
 		err = errors.Wrapf(err, "cannot set desired composed resources in %T", r)
+		return
+	}
+
+	for k, v := range c.context.GetFields() {
+		response.SetContextKey(r, k, v)
+	}
+
+	if len(c.requirements) > 0 {
+		r.Requirements = &fnv1.Requirements{
+			ExtraResources: c.requirements,
+		}
 	}
+
+	c.setResults(r)
+	c.setTTL(r)
 	return
 }
 
+// DesiredOption configures how a single resource is added by `AddDesired`
+type DesiredOption func(*desiredOptions)
+
+type desiredOptions struct {
+	readiness ReadinessCheck
+	merge     MergeStrategy
+	listKeys  map[string]string
+	conflicts ConflictMode
+
+	dependencies []dependency
+
+	noPropagation bool
+}
+
 // AddDesired takes an unstructured object and adds it to the desired composed
 // resources
 //
-// If the object exists on the stack already, we do a deepEqual to see if the
-// object has changed and if not, this method won't do anything.
+// If the object exists on the stack already, it is combined with the existing
+// object using the merge strategy given with `WithMergeStrategy`. By default
+// the existing object is replaced. If the result hasn't changed, this method
+// won't do anything other than refresh readiness when a readiness strategy is
+// given.
 //
 //   - `n` The name of the composite resource to add. This is the pipeline name
 //     and not the metadata name
 //   - `u` The unstructured object to add to the set of desired resources
-func (c *Composition) AddDesired(n string, u *unstructured.Unstructured) (err error) {
+//   - `opts` Options for this resource such as `WithReadiness`,
+//     `WithMergeStrategy` or `DependsOnReady`
+func (c *TypedComposition[XR, In]) AddDesired(n string, u *unstructured.Unstructured, opts ...DesiredOption) (err error) {
+	options := &desiredOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if c.generated == nil {
+		c.generated = make(map[resource.Name]struct{})
+	}
+	c.generated[resource.Name(n)] = struct{}{}
+
+	if len(options.dependencies) > 0 {
+		if c.dependencies == nil {
+			c.dependencies = make(map[resource.Name][]dependency)
+		}
+		c.dependencies[resource.Name(n)] = options.dependencies
+	}
+
+	if c.propagation != nil && !options.noPropagation {
+		if u, err = c.propagate(u); err != nil {
+			err = errors.Wrapf(err, "cannot propagate metadata to %q", n)
+			return
+		}
+	}
+
+	object := u.Object
 	if o, ok := c.DesiredComposed[resource.Name(n)]; ok {
+		m := &merger{
+			strategy: options.merge,
+			listKeys: options.listKeys,
+		}
+		if object, err = m.merge(o.Resource.Object, u.Object); err != nil {
+			err = errors.Wrapf(err, "cannot merge desired resource %q", n)
+			return
+		}
+
+		if len(m.conflicts) > 0 {
+			mc := &MergeConflict{
+				ErrorContext: objectContext(object, ""),
+				Name:         n,
+				Paths:        m.conflicts,
+			}
+
+			switch options.conflicts {
+			case ConflictReport:
+				c.Warning("MergeConflict", mc)
+			case ConflictError:
+				err = mc
+				return
+			}
+		}
+
 		// Object exists and hasn't changed
-		if reflect.DeepEqual(o.Resource.Object, u.Object) {
+		if reflect.DeepEqual(o.Resource.Object, object) {
+			if options.readiness != nil {
+				o.Ready = c.readiness(n, options.readiness)
+			}
 			return
 		}
 	}
+
+	ready := resource.ReadyTrue
+	if options.readiness != nil {
+		ready = c.readiness(n, options.readiness)
+	}
+
 	c.DesiredComposed[resource.Name(n)] = &resource.DesiredComposed{
 		Resource: &composed.Unstructured{
-			Unstructured: *u,
+			Unstructured: unstructured.Unstructured{
+				Object: object,
+			},
 		},
-		Ready: resource.ReadyTrue,
+		Ready: ready,
 	}
 	return
 }
+
+// readiness evaluates check against the observed resource with pipeline name n
+func (c *TypedComposition[XR, In]) readiness(n string, check ReadinessCheck) resource.Ready {
+	var observed *composed.Unstructured
+	if o, ok := c.ObservedComposed[resource.Name(n)]; ok {
+		observed = o.Resource
+	}
+	return check(observed)
+}
@@ -0,0 +1,90 @@
+package composite
+
+import (
+	"encoding/json"
+	"testing"
+
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"github.com/crossplane/function-sdk-go/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// testXR is the observed composite resource used when a test doesn't need its
+// own
+const testXR = `{
+	"apiVersion": "test.xfnlib.io/v1",
+	"kind": "XTest",
+	"metadata": {"name": "xr"},
+	"spec": {"region": "eu-west-1"}
+}`
+
+// testInput is the function input of the test requests
+const testInput = `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input"}`
+
+// testComposition is the composition type used in tests
+type testComposition = TypedComposition[map[string]any, *unstructured.Unstructured]
+
+// testRequest builds a request from JSON documents
+//
+//   - `xr` The observed composite resource. Defaults to `testXR`
+//   - `observed` The observed composed resources by pipeline name
+//   - `desired` The desired composed resources by pipeline name
+func testRequest(t *testing.T, xr string, observed, desired map[string]string) *fnv1.RunFunctionRequest {
+	t.Helper()
+
+	if xr == "" {
+		xr = testXR
+	}
+
+	req := &fnv1.RunFunctionRequest{
+		Observed: &fnv1.State{
+			Composite: &fnv1.Resource{Resource: resource.MustStructJSON(xr)},
+			Resources: make(map[string]*fnv1.Resource, len(observed)),
+		},
+		Desired: &fnv1.State{
+			Composite: &fnv1.Resource{Resource: resource.MustStructJSON(`{}`)},
+			Resources: make(map[string]*fnv1.Resource, len(desired)),
+		},
+		Input: resource.MustStructJSON(testInput),
+	}
+
+	for n, o := range observed {
+		req.Observed.Resources[n] = &fnv1.Resource{Resource: resource.MustStructJSON(o)}
+	}
+	for n, d := range desired {
+		req.Desired.Resources[n] = &fnv1.Resource{Resource: resource.MustStructJSON(d)}
+	}
+	return req
+}
+
+// newTestComposition reads req into a composition and fails the test on error
+func newTestComposition(t *testing.T, req *fnv1.RunFunctionRequest, opts ...Option) *testComposition {
+	t.Helper()
+
+	c, err := NewTyped[map[string]any](req, &unstructured.Unstructured{}, opts...)
+	if err != nil {
+		t.Fatalf("NewTyped(...): unexpected error: %v", err)
+	}
+	return c
+}
+
+// testObject builds an unstructured object from JSON
+func testObject(t *testing.T, s string) *unstructured.Unstructured {
+	t.Helper()
+
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON([]byte(s)); err != nil {
+		t.Fatalf("cannot unmarshal %s: %v", s, err)
+	}
+	return u
+}
+
+// testMap decodes a JSON object that may be a partial kubernetes object
+func testMap(t *testing.T, s string) (m map[string]any) {
+	t.Helper()
+
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		t.Fatalf("cannot unmarshal %s: %v", s, err)
+	}
+	return
+}
@@ -0,0 +1,139 @@
+package composite
+
+import (
+	"encoding/base64"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/function-sdk-go/resource"
+)
+
+// ConnectionDetailOption configures how `MapConnectionDetails` copies
+// connection details onto the composite resource
+type ConnectionDetailOption func(*connectionDetailOptions)
+
+type connectionDetailOptions struct {
+	keys   map[string]struct{}
+	rename map[string]string
+	prefix string
+	decode bool
+	encode bool
+}
+
+// WithConnectionKeys only copies the given keys. By default all keys are copied
+func WithConnectionKeys(keys ...string) ConnectionDetailOption {
+	return func(o *connectionDetailOptions) {
+		if o.keys == nil {
+			o.keys = make(map[string]struct{}, len(keys))
+		}
+		for _, k := range keys {
+			o.keys[k] = struct{}{}
+		}
+	}
+}
+
+// WithConnectionKeyRename publishes the key `from` as `to` on the composite
+// resource
+func WithConnectionKeyRename(from, to string) ConnectionDetailOption {
+	return func(o *connectionDetailOptions) {
+		if o.rename == nil {
+			o.rename = make(map[string]string)
+		}
+		o.rename[from] = to
+	}
+}
+
+// WithConnectionKeyPrefix prefixes every key that is not explicitly renamed
+func WithConnectionKeyPrefix(prefix string) ConnectionDetailOption {
+	return func(o *connectionDetailOptions) {
+		o.prefix = prefix
+	}
+}
+
+// WithBase64Decode decodes values that are stored base64 encoded on the
+// composed resource
+func WithBase64Decode() ConnectionDetailOption {
+	return func(o *connectionDetailOptions) {
+		o.decode = true
+		o.encode = false
+	}
+}
+
+// WithBase64Encode base64 encodes values before publishing them
+func WithBase64Encode() ConnectionDetailOption {
+	return func(o *connectionDetailOptions) {
+		o.encode = true
+		o.decode = false
+	}
+}
+
+// SetConnectionDetail sets a single connection detail on the desired composite
+// resource
+func (c *TypedComposition[XR, In]) SetConnectionDetail(key string, value []byte) {
+	if c.DesiredComposite.ConnectionDetails == nil {
+		c.DesiredComposite.ConnectionDetails = make(resource.ConnectionDetails)
+	}
+	c.DesiredComposite.ConnectionDetails[key] = value
+}
+
+// SetConnectionDetails merges the given connection details into those of the
+// desired composite resource
+func (c *TypedComposition[XR, In]) SetConnectionDetails(details resource.ConnectionDetails) {
+	for k, v := range details {
+		c.SetConnectionDetail(k, v)
+	}
+}
+
+// MapConnectionDetails copies the connection details of an observed composed
+// resource onto the desired composite resource
+//
+// Nothing is copied when the composed resource has not been observed yet or
+// when any value fails to decode.
+//
+//   - `n` The pipeline name of the composed resource
+//   - `opts` Options to filter, rename and encode the connection details
+func (c *TypedComposition[XR, In]) MapConnectionDetails(n string, opts ...ConnectionDetailOption) (err error) {
+	options := &connectionDetailOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	observed, ok := c.ObservedComposed[resource.Name(n)]
+	if !ok {
+		return
+	}
+
+	// Values are collected first so a value failing to decode leaves the
+	// connection details of the composite untouched
+	details := make(resource.ConnectionDetails, len(observed.ConnectionDetails))
+	for k, v := range observed.ConnectionDetails {
+		if options.keys != nil {
+			if _, ok := options.keys[k]; !ok {
+				continue
+			}
+		}
+
+		key := options.prefix + k
+		if to, ok := options.rename[k]; ok {
+			key = to
+		}
+
+		value := v
+		switch {
+		case options.decode:
+			value = make([]byte, base64.StdEncoding.DecodedLen(len(v)))
+			var l int
+			if l, err = base64.StdEncoding.Decode(value, v); err != nil {
+				err = errors.Wrapf(err, "cannot decode connection detail %q of %q", k, n)
+				return
+			}
+			value = value[:l]
+		case options.encode:
+			value = []byte(base64.StdEncoding.EncodeToString(v))
+		}
+
+		details[key] = value
+	}
+
+	c.SetConnectionDetails(details)
+	return
+}
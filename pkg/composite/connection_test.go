@@ -0,0 +1,91 @@
+package composite
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/crossplane/function-sdk-go/resource"
+)
+
+func TestMapConnectionDetails(t *testing.T) {
+	cases := map[string]struct {
+		details  map[string]string
+		existing resource.ConnectionDetails
+		opts     []ConnectionDetailOption
+		want     resource.ConnectionDetails
+		wantErr  bool
+	}{
+		"CopiesAll": {
+			details: map[string]string{"username": "admin", "password": "secret"},
+			want:    resource.ConnectionDetails{"username": []byte("admin"), "password": []byte("secret")},
+		},
+		"Keys": {
+			details: map[string]string{"username": "admin", "password": "secret"},
+			opts:    []ConnectionDetailOption{WithConnectionKeys("password")},
+			want:    resource.ConnectionDetails{"password": []byte("secret")},
+		},
+		"RenameAndPrefix": {
+			details: map[string]string{"username": "admin", "password": "secret"},
+			opts: []ConnectionDetailOption{
+				WithConnectionKeyPrefix("db-"),
+				WithConnectionKeyRename("password", "pass"),
+			},
+			want: resource.ConnectionDetails{"db-username": []byte("admin"), "pass": []byte("secret")},
+		},
+		"Decode": {
+			details: map[string]string{"password": "c2VjcmV0"},
+			opts:    []ConnectionDetailOption{WithBase64Decode()},
+			want:    resource.ConnectionDetails{"password": []byte("secret")},
+		},
+		"Encode": {
+			details: map[string]string{"password": "secret"},
+			opts:    []ConnectionDetailOption{WithBase64Encode()},
+			want:    resource.ConnectionDetails{"password": []byte("c2VjcmV0")},
+		},
+		"MergesExisting": {
+			details:  map[string]string{"password": "secret"},
+			existing: resource.ConnectionDetails{"endpoint": []byte("db.local")},
+			want:     resource.ConnectionDetails{"endpoint": []byte("db.local"), "password": []byte("secret")},
+		},
+		"DecodeErrorKeepsExisting": {
+			details:  map[string]string{"a": "YQ==", "b": "%", "c": "Yw=="},
+			existing: resource.ConnectionDetails{"endpoint": []byte("db.local")},
+			opts:     []ConnectionDetailOption{WithBase64Decode()},
+			want:     resource.ConnectionDetails{"endpoint": []byte("db.local")},
+			wantErr:  true,
+		},
+		"NotObserved": {
+			existing: resource.ConnectionDetails{"endpoint": []byte("db.local")},
+			want:     resource.ConnectionDetails{"endpoint": []byte("db.local")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := testRequest(t, "", nil, nil)
+			if tc.details != nil {
+				req = testRequest(t, "", map[string]string{"db": testObservedBucket}, nil)
+				req.Observed.Resources["db"].ConnectionDetails = make(map[string][]byte, len(tc.details))
+				for k, v := range tc.details {
+					req.Observed.Resources["db"].ConnectionDetails[k] = []byte(v)
+				}
+			}
+
+			c := newTestComposition(t, req)
+			c.SetConnectionDetails(tc.existing)
+
+			err := c.MapConnectionDetails("db", tc.opts...)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("MapConnectionDetails(...): error = %v, want error %v", err, tc.wantErr)
+			}
+
+			got := c.DesiredComposite.ConnectionDetails
+			if len(got) == 0 && len(tc.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("MapConnectionDetails(...): details = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
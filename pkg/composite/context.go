@@ -0,0 +1,89 @@
+package composite
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ContextKey is a key in the pipeline context holding a value of type T
+//
+// Declare keys once and share them between the functions of a pipeline so
+// that both sides agree on the type of the value.
+//
+// Example:
+//
+//	var VpcKey = composite.ContextKey[VpcInfo]("giantswarm.io/vpc")
+type ContextKey[T any] string
+
+// EnvironmentKey is the context key Crossplane stores the environment under
+const EnvironmentKey ContextKey[map[string]any] = "apiextensions.crossplane.io/environment"
+
+// GetContext reads a value from the pipeline context
+//
+// `found` is false when the key is not set in the context.
+//
+// Example:
+//
+//	vpc, found, err := composite.GetContext(composed, VpcKey)
+func GetContext[T any, XR any, In InputProvider](c *TypedComposition[XR, In], key ContextKey[T]) (v T, found bool, err error) {
+	var value *structpb.Value
+	if value, found = c.context.GetFields()[string(key)]; !found {
+		return
+	}
+
+	if err = To(value.AsInterface(), &v); err != nil {
+		err = errors.Wrapf(err, "cannot convert context key %q to %T", key, v)
+	}
+	return
+}
+
+// SetContext writes a value to the pipeline context
+//
+// The context is passed on to the next function in the pipeline when
+// `ToResponse` is called.
+func SetContext[T any, XR any, In InputProvider](c *TypedComposition[XR, In], key ContextKey[T], v T) (err error) {
+	var (
+		raw   any
+		value *structpb.Value
+	)
+	if err = To(v, &raw); err != nil {
+		err = errors.Wrapf(err, "cannot convert %T for context key %q", v, key)
+		return
+	}
+
+	if value, err = structpb.NewValue(raw); err != nil {
+		err = errors.Wrapf(err, "cannot convert %T for context key %q", v, key)
+		return
+	}
+
+	if c.context == nil {
+		c.context = &structpb.Struct{}
+	}
+
+	if c.context.Fields == nil {
+		c.context.Fields = make(map[string]*structpb.Value)
+	}
+	c.context.Fields[string(key)] = value
+	return
+}
+
+// Environment decodes the environment Crossplane placed in the pipeline
+// context into `into`
+//
+// `found` is false when no environment has been set.
+func (c *TypedComposition[XR, In]) Environment(into any) (found bool, err error) {
+	var value *structpb.Value
+	if value, found = c.context.GetFields()[string(EnvironmentKey)]; !found {
+		return
+	}
+
+	if err = To(value.AsInterface(), into); err != nil {
+		err = errors.Wrapf(err, "cannot convert environment to %T", into)
+	}
+	return
+}
+
+// Context returns a copy of the whole pipeline context as plain values
+func (c *TypedComposition[XR, In]) Context() map[string]any {
+	return c.context.AsMap()
+}
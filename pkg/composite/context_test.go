@@ -0,0 +1,176 @@
+package composite
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/crossplane/function-sdk-go/resource"
+	"github.com/crossplane/function-sdk-go/response"
+)
+
+// testVpc is a typed context value
+type testVpc struct {
+	ID      string   `json:"id"`
+	Subnets []string `json:"subnets,omitempty"`
+}
+
+func TestGetContext(t *testing.T) {
+	context := `{
+		"giantswarm.io/vpc": {"id": "vpc-1", "subnets": ["a", "b"]},
+		"giantswarm.io/name": "cluster"
+	}`
+
+	cases := map[string]struct {
+		key       ContextKey[testVpc]
+		want      testVpc
+		wantFound bool
+		wantErr   bool
+	}{
+		"Found": {
+			key:       "giantswarm.io/vpc",
+			want:      testVpc{ID: "vpc-1", Subnets: []string{"a", "b"}},
+			wantFound: true,
+		},
+		"Missing": {
+			key: "giantswarm.io/missing",
+		},
+		"WrongType": {
+			key:       "giantswarm.io/name",
+			wantFound: true,
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := testRequest(t, "", nil, nil)
+			req.Context = resource.MustStructJSON(context)
+			c := newTestComposition(t, req)
+
+			got, found, err := GetContext(c, tc.key)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("GetContext(...): error = %v, want error %v", err, tc.wantErr)
+			}
+			if found != tc.wantFound {
+				t.Errorf("GetContext(...): found = %v, want %v", found, tc.wantFound)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("GetContext(...) = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetContext(t *testing.T) {
+	key := ContextKey[testVpc]("giantswarm.io/vpc")
+
+	cases := map[string]struct {
+		context string
+		value   testVpc
+		want    map[string]any
+	}{
+		"EmptyContext": {
+			value: testVpc{ID: "vpc-1"},
+			want: map[string]any{
+				"giantswarm.io/vpc": map[string]any{"id": "vpc-1"},
+			},
+		},
+		"KeepsOtherKeys": {
+			context: `{"giantswarm.io/name": "cluster"}`,
+			value:   testVpc{ID: "vpc-1", Subnets: []string{"a"}},
+			want: map[string]any{
+				"giantswarm.io/name": "cluster",
+				"giantswarm.io/vpc":  map[string]any{"id": "vpc-1", "subnets": []any{"a"}},
+			},
+		},
+		"Replaces": {
+			context: `{"giantswarm.io/vpc": {"id": "vpc-0"}}`,
+			value:   testVpc{ID: "vpc-1"},
+			want: map[string]any{
+				"giantswarm.io/vpc": map[string]any{"id": "vpc-1"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := testRequest(t, "", nil, nil)
+			if tc.context != "" {
+				req.Context = resource.MustStructJSON(tc.context)
+			}
+			c := newTestComposition(t, req)
+			before := req.GetContext().AsMap()
+
+			if err := SetContext(c, key, tc.value); err != nil {
+				t.Fatalf("SetContext(...): unexpected error: %v", err)
+			}
+
+			got, found, err := GetContext(c, key)
+			if err != nil || !found || !reflect.DeepEqual(got, tc.value) {
+				t.Errorf("GetContext(...) = %+v, %v, %v, want %+v, true, nil", got, found, err, tc.value)
+			}
+
+			if got := req.GetContext().AsMap(); !reflect.DeepEqual(got, before) {
+				t.Errorf("SetContext(...): request context = %v, want it unchanged", got)
+			}
+
+			rsp := response.To(req, response.DefaultTTL)
+			if err := c.ToResponse(rsp); err != nil {
+				t.Fatalf("ToResponse(...): unexpected error: %v", err)
+			}
+			if got := rsp.GetContext().AsMap(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ToResponse(...): context = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	cases := map[string]struct {
+		context   string
+		want      map[string]any
+		wantFound bool
+	}{
+		"Found": {
+			context:   `{"apiextensions.crossplane.io/environment": {"region": "eu-west-1"}}`,
+			want:      map[string]any{"region": "eu-west-1"},
+			wantFound: true,
+		},
+		"Missing": {
+			context: `{"giantswarm.io/name": "cluster"}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := testRequest(t, "", nil, nil)
+			req.Context = resource.MustStructJSON(tc.context)
+			c := newTestComposition(t, req)
+
+			var got map[string]any
+			found, err := c.Environment(&got)
+			if err != nil {
+				t.Fatalf("Environment(...): unexpected error: %v", err)
+			}
+			if found != tc.wantFound || !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Environment(...) = %v, %v, want %v, %v", got, found, tc.want, tc.wantFound)
+			}
+
+			if _, found, _ := GetContext(c, EnvironmentKey); found != tc.wantFound {
+				t.Errorf("GetContext(EnvironmentKey): found = %v, want %v", found, tc.wantFound)
+			}
+		})
+	}
+}
+
+func TestContextIsACopy(t *testing.T) {
+	req := testRequest(t, "", nil, nil)
+	req.Context = resource.MustStructJSON(`{"giantswarm.io/name": "cluster"}`)
+	c := newTestComposition(t, req)
+
+	c.Context()["giantswarm.io/name"] = "changed"
+
+	if got := c.Context()["giantswarm.io/name"]; got != "cluster" {
+		t.Errorf("Context() = %v, want the copy to be independent", got)
+	}
+}
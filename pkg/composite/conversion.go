@@ -0,0 +1,126 @@
+package composite
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// InputConversion converts an input of an older version into the hub version
+type InputConversion[T any, Hub InputProvider] func(from *T, to Hub) error
+
+// InputRegistry holds the versions of the function input a function accepts
+//
+// The hub is the version the function works with. Every other registered
+// version is converted to the hub when the composition is created and is
+// reported as deprecated.
+type InputRegistry struct {
+	hub      string
+	versions map[string]func(input map[string]any, into runtime.Object) error
+}
+
+// NewInputRegistry creates an input registry whose hub is `hubAPIVersion`, for
+// example `template.fn.giantswarm.io/v1beta1`
+func NewInputRegistry(hubAPIVersion string) *InputRegistry {
+	return &InputRegistry{
+		hub:      hubAPIVersion,
+		versions: make(map[string]func(map[string]any, runtime.Object) error),
+	}
+}
+
+// RegisterInputVersion registers an older version of the function input
+//
+// Inputs of `apiVersion` are decoded into a `T` and converted into the hub
+// with `convert`. `Hub` must be the input type handed to `New` or `NewTyped`.
+//
+// Example:
+//
+//	var inputs = composite.NewInputRegistry(v1beta1.GroupVersion.String())
+//
+//	func init() {
+//		composite.RegisterInputVersion(inputs, v1alpha1.GroupVersion.String(),
+//			func(from *v1alpha1.Input, to *v1beta1.Input) error {
+//				to.Spec.Region = from.Spec.Location
+//				return nil
+//			},
+//		)
+//	}
+func RegisterInputVersion[T any, Hub InputProvider](r *InputRegistry, apiVersion string, convert InputConversion[T, Hub]) {
+	r.versions[apiVersion] = func(input map[string]any, into runtime.Object) (err error) {
+		hub, ok := into.(Hub)
+		if !ok {
+			var want Hub
+			return errors.Errorf("cannot convert input %s into %T, the registered hub is %T", apiVersion, into, want)
+		}
+
+		from := new(T)
+		if err = To(input, from); err != nil {
+			return errors.Wrapf(err, "cannot decode input %s into %T", apiVersion, from)
+		}
+		return errors.Wrapf(convert(from, hub), "cannot convert input %s to hub", apiVersion)
+	}
+}
+
+// WithInputRegistry accepts every version of the function input registered
+// with `r`
+//
+// Inputs of an older version are converted to the hub version and a warning
+// result telling the user to move to the hub version is emitted.
+func WithInputRegistry(r *InputRegistry) Option {
+	return func(o *options) {
+		o.inputRegistry = r
+	}
+}
+
+// supported returns the sorted list of accepted input versions
+func (r *InputRegistry) supported() []string {
+	versions := []string{r.hub}
+	for v := range r.versions {
+		versions = append(versions, v)
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+// convertInput converts input to the hub version and decodes it into the
+// input of the composition
+//
+// `converted` is false when the input already is of the hub version.
+func (c *TypedComposition[XR, In]) convertInput(input map[string]any, o *options) (converted bool, err error) {
+	apiVersion, _ := input["apiVersion"].(string)
+	if o.inputRegistry == nil || apiVersion == "" || apiVersion == o.inputRegistry.hub {
+		return
+	}
+
+	convert, ok := o.inputRegistry.versions[apiVersion]
+	if !ok {
+		err = &UnsupportedInputVersion{
+			APIVersion: apiVersion,
+			Supported:  o.inputRegistry.supported(),
+		}
+		return
+	}
+
+	if err = convert(input, c.Input); err != nil {
+		return
+	}
+	converted = true
+
+	kind, _ := input["kind"].(string)
+	c.Input.GetObjectKind().SetGroupVersionKind(schema.FromAPIVersionAndKind(o.inputRegistry.hub, kind))
+
+	message := fmt.Sprintf("function input %s is deprecated, use %s instead", apiVersion, o.inputRegistry.hub)
+	if o.log != nil {
+		o.log.Info("Function input version is deprecated", "apiVersion", apiVersion, "hub", o.inputRegistry.hub)
+	}
+	c.AddResult(Result{
+		Severity: fnv1.Severity_SEVERITY_WARNING,
+		Reason:   "DeprecatedInput",
+		Message:  message,
+	})
+	return
+}
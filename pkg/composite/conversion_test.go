@@ -0,0 +1,149 @@
+package composite
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"github.com/crossplane/function-sdk-go/resource"
+	"github.com/crossplane/function-sdk-go/response"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestInputConversion(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	registry := NewInputRegistry("test.xfnlib.io/v1")
+	RegisterInputVersion(registry, "test.xfnlib.io/v1alpha1",
+		func(from *testInputV1alpha1, to *unstructured.Unstructured) error {
+			to.Object = map[string]any{"spec": map[string]any{"name": from.Spec.Title}}
+			return nil
+		},
+	)
+	RegisterInputVersion(registry, "test.xfnlib.io/v1alpha2",
+		func(_ *testInputV1alpha1, _ *unstructured.Unstructured) error {
+			return errBoom
+		},
+	)
+	RegisterInputVersion(registry, "test.xfnlib.io/v1alpha3",
+		func(_ *testInputV1alpha1, _ *unstructured.UnstructuredList) error {
+			return nil
+		},
+	)
+
+	cases := map[string]struct {
+		input           string
+		registry        *InputRegistry
+		want            string
+		wantResults     []*fnv1.Result
+		wantErr         bool
+		wantErrIs       error
+		wantUnsupported *UnsupportedInputVersion
+	}{
+		"Hub": {
+			input:    `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input", "spec": {"name": "a"}}`,
+			registry: registry,
+			want:     `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input", "spec": {"name": "a"}}`,
+		},
+		"Converted": {
+			input:    `{"apiVersion": "test.xfnlib.io/v1alpha1", "kind": "Input", "spec": {"title": "a"}}`,
+			registry: registry,
+			want:     `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input", "spec": {"name": "a"}}`,
+			wantResults: []*fnv1.Result{{
+				Severity: fnv1.Severity_SEVERITY_WARNING,
+				Reason:   proto.String("DeprecatedInput"),
+				Message:  "function input test.xfnlib.io/v1alpha1 is deprecated, use test.xfnlib.io/v1 instead",
+				Target:   fnv1.Target_TARGET_COMPOSITE.Enum(),
+			}},
+		},
+		"UnregisteredVersion": {
+			input:    `{"apiVersion": "test.xfnlib.io/v2", "kind": "Input"}`,
+			registry: registry,
+			wantErr:  true,
+			wantUnsupported: &UnsupportedInputVersion{
+				APIVersion: "test.xfnlib.io/v2",
+				Supported: []string{
+					"test.xfnlib.io/v1",
+					"test.xfnlib.io/v1alpha1",
+					"test.xfnlib.io/v1alpha2",
+					"test.xfnlib.io/v1alpha3",
+				},
+			},
+		},
+		"ConversionFails": {
+			input:     `{"apiVersion": "test.xfnlib.io/v1alpha2", "kind": "Input"}`,
+			registry:  registry,
+			wantErr:   true,
+			wantErrIs: errBoom,
+		},
+		"HubMismatch": {
+			input:    `{"apiVersion": "test.xfnlib.io/v1alpha3", "kind": "Input"}`,
+			registry: registry,
+			wantErr:  true,
+		},
+		"UndecodableInput": {
+			input:    `{"apiVersion": "test.xfnlib.io/v1alpha1", "kind": "Input", "spec": {"title": 1}}`,
+			registry: registry,
+			wantErr:  true,
+		},
+		"NoAPIVersion": {
+			input:    `{"kind": "Input", "spec": {"title": "a"}}`,
+			registry: registry,
+			want:     `{"kind": "Input", "spec": {"title": "a"}}`,
+		},
+		"NoRegistry": {
+			input: `{"apiVersion": "test.xfnlib.io/v1alpha1", "kind": "Input", "spec": {"title": "a"}}`,
+			want:  `{"apiVersion": "test.xfnlib.io/v1alpha1", "kind": "Input", "spec": {"title": "a"}}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := testRequest(t, "", nil, nil)
+			req.Input = resource.MustStructJSON(tc.input)
+
+			var opts []Option
+			if tc.registry != nil {
+				opts = append(opts, WithInputRegistry(tc.registry))
+			}
+
+			c, err := NewTyped[map[string]any](req, &unstructured.Unstructured{}, opts...)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NewTyped(...): error = %v, want error %v", err, tc.wantErr)
+			}
+			if tc.wantErrIs != nil && !errors.Is(err, tc.wantErrIs) {
+				t.Errorf("NewTyped(...): error = %v, want %v", err, tc.wantErrIs)
+			}
+			if tc.wantUnsupported != nil {
+				var got *UnsupportedInputVersion
+				if !errors.As(err, &got) || !reflect.DeepEqual(got, tc.wantUnsupported) {
+					t.Errorf("NewTyped(...): error = %#v, want %#v", got, tc.wantUnsupported)
+				}
+			}
+			if tc.wantErr {
+				return
+			}
+
+			if got, want := c.Input.Object, testMap(t, tc.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("NewTyped(...): input = %v, want %v", got, want)
+			}
+
+			rsp := response.To(req, response.DefaultTTL)
+			if err := c.ToResponse(rsp); err != nil {
+				t.Fatalf("ToResponse(...): unexpected error: %v", err)
+			}
+
+			got := rsp.GetResults()
+			if len(got) != len(tc.wantResults) {
+				t.Fatalf("ToResponse(...): results = %v, want %v", got, tc.wantResults)
+			}
+			for i := range got {
+				if !proto.Equal(got[i], tc.wantResults[i]) {
+					t.Errorf("ToResponse(...): result %d = %v, want %v", i, got[i], tc.wantResults[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package composite
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/crossplane/function-sdk-go/resource"
+)
+
+// dependency is a condition a desired composed resource waits on
+type dependency struct {
+	name        string
+	check       ReadinessCheck
+	description string
+}
+
+// DependsOn holds the resource back until `check` reports the observed
+// composed resource `n` as ready
+//
+// Use this with any `ReadinessCheck` where `DependsOnReady` and
+// `DependsOnField` don't fit.
+//
+//   - `n` The pipeline name of the resource depended on
+//   - `check` The condition the observed resource must satisfy
+func DependsOn(n string, check ReadinessCheck) DesiredOption {
+	return func(o *desiredOptions) {
+		o.dependencies = append(o.dependencies, dependency{
+			name:        n,
+			check:       check,
+			description: fmt.Sprintf("%q", n),
+		})
+	}
+}
+
+// DependsOnReady holds the resource back until the observed composed resource
+// `n` reports a `Ready` condition with status `True`
+//
+// Example:
+//
+//	err := composed.AddDesired("subnet", subnet, composite.DependsOnReady("vpc"))
+func DependsOnReady(n string) DesiredOption {
+	return func(o *desiredOptions) {
+		o.dependencies = append(o.dependencies, dependency{
+			name:        n,
+			check:       ReadyFromObserved(),
+			description: fmt.Sprintf("%q to be ready", n),
+		})
+	}
+}
+
+// DependsOnField holds the resource back until `path` is set on the observed
+// composed resource `n`
+//
+//   - `n` The pipeline name of the resource depended on
+//   - `path` A field path such as `status.atProvider.id`
+//
+// Example:
+//
+//	err := composed.AddDesired("subnet", subnet,
+//		composite.DependsOnField("vpc", "status.atProvider.id"),
+//	)
+func DependsOnField(n, path string) DesiredOption {
+	return func(o *desiredOptions) {
+		o.dependencies = append(o.dependencies, dependency{
+			name:        n,
+			check:       ReadyWhenFieldExists(path),
+			description: fmt.Sprintf("%s of %q", path, n),
+		})
+	}
+}
+
+// GatedResource is a desired composed resource held back by `ToResponse`
+// because its dependencies are not satisfied
+type GatedResource struct {
+	// Name is the pipeline name of the resource
+	Name resource.Name
+
+	// WaitingFor describes the unsatisfied dependencies
+	WaitingFor []string
+
+	// Kept is true when the previously observed form of the resource is kept
+	// instead of dropping it
+	Kept bool
+}
+
+// Gated returns the desired composed resources whose dependencies are not
+// satisfied, sorted by name
+//
+// These are held back by `ToResponse`. A resource that does not exist yet is
+// not created, a resource that exists keeps its observed form until its
+// dependencies are satisfied again.
+func (c *TypedComposition[XR, In]) Gated() (gated []GatedResource) {
+	for n, deps := range c.dependencies {
+		if _, ok := c.DesiredComposed[n]; !ok {
+			continue
+		}
+
+		var waiting []string
+		for _, d := range deps {
+			if c.readiness(d.name, d.check) != resource.ReadyTrue {
+				waiting = append(waiting, d.description)
+			}
+		}
+
+		if len(waiting) == 0 {
+			continue
+		}
+
+		_, kept := c.ObservedComposed[n]
+		gated = append(gated, GatedResource{
+			Name:       n,
+			WaitingFor: waiting,
+			Kept:       kept,
+		})
+	}
+
+	slices.SortFunc(gated, func(a, b GatedResource) int {
+		return strings.Compare(string(a.Name), string(b.Name))
+	})
+	return
+}
+
+// gate holds back the desired composed resources whose dependencies are not
+// satisfied and reports them as results
+func (c *TypedComposition[XR, In]) gate() {
+	c.gated = c.Gated()
+	for _, g := range c.gated {
+		if g.Kept {
+			d := c.DesiredComposed[g.Name]
+			d.Resource = keptObject(c.ObservedComposed[g.Name].Resource)
+		} else {
+			delete(c.DesiredComposed, g.Name)
+		}
+
+		c.Normalf("WaitingForDependencies", "composed resource %q is waiting for %s", g.Name, strings.Join(g.WaitingFor, ", "))
+	}
+}
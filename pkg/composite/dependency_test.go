@@ -0,0 +1,146 @@
+package composite
+
+import (
+	"reflect"
+	"testing"
+
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+)
+
+const (
+	testVPCReady = `{
+		"apiVersion": "ec2.aws.upbound.io/v1beta1",
+		"kind": "VPC",
+		"metadata": {"name": "vpc"},
+		"status": {
+			"atProvider": {"id": "vpc-1"},
+			"conditions": [{"type": "Ready", "status": "True"}]
+		}
+	}`
+
+	testVPCNotReady = `{
+		"apiVersion": "ec2.aws.upbound.io/v1beta1",
+		"kind": "VPC",
+		"metadata": {"name": "vpc"},
+		"status": {"conditions": [{"type": "Ready", "status": "False"}]}
+	}`
+
+	testSubnet = `{
+		"apiVersion": "ec2.aws.upbound.io/v1beta1",
+		"kind": "Subnet",
+		"metadata": {"name": "subnet"},
+		"spec": {"forProvider": {"cidrBlock": "10.0.1.0/24"}}
+	}`
+)
+
+func TestGated(t *testing.T) {
+	cases := map[string]struct {
+		observed map[string]string
+		opts     []DesiredOption
+		want     []GatedResource
+	}{
+		"NoDependencies": {},
+		"ReadySatisfied": {
+			observed: map[string]string{"vpc": testVPCReady},
+			opts:     []DesiredOption{DependsOnReady("vpc")},
+		},
+		"FieldSatisfied": {
+			observed: map[string]string{"vpc": testVPCReady},
+			opts:     []DesiredOption{DependsOnField("vpc", "status.atProvider.id")},
+		},
+		"NotObserved": {
+			opts: []DesiredOption{DependsOnReady("vpc")},
+			want: []GatedResource{{Name: "subnet", WaitingFor: []string{`"vpc" to be ready`}}},
+		},
+		"NotReady": {
+			observed: map[string]string{"vpc": testVPCNotReady},
+			opts: []DesiredOption{
+				DependsOnReady("vpc"),
+				DependsOnField("vpc", "status.atProvider.id"),
+			},
+			want: []GatedResource{{
+				Name:       "subnet",
+				WaitingFor: []string{`"vpc" to be ready`, `status.atProvider.id of "vpc"`},
+			}},
+		},
+		"KeepsExistingResource": {
+			observed: map[string]string{"vpc": testVPCNotReady, "subnet": testSubnet},
+			opts:     []DesiredOption{DependsOnReady("vpc")},
+			want:     []GatedResource{{Name: "subnet", WaitingFor: []string{`"vpc" to be ready`}, Kept: true}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestComposition(t, testRequest(t, "", tc.observed, nil))
+			if err := c.AddDesired("subnet", testObject(t, testSubnet), tc.opts...); err != nil {
+				t.Fatalf("AddDesired(...): unexpected error: %v", err)
+			}
+
+			if got := c.Gated(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Gated() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestToResponseGatesResources(t *testing.T) {
+	observedSubnet := `{
+		"apiVersion": "ec2.aws.upbound.io/v1beta1",
+		"kind": "Subnet",
+		"metadata": {"name": "subnet", "uid": "1"},
+		"spec": {"forProvider": {"cidrBlock": "10.0.0.0/24"}},
+		"status": {"atProvider": {"id": "subnet-1"}}
+	}`
+
+	cases := map[string]struct {
+		observed map[string]string
+		want     map[string]any
+	}{
+		"HoldsBackNewResource": {
+			observed: map[string]string{"vpc": testVPCNotReady},
+		},
+		"KeepsObservedForm": {
+			observed: map[string]string{"vpc": testVPCNotReady, "subnet": observedSubnet},
+			want: map[string]any{
+				"apiVersion": "ec2.aws.upbound.io/v1beta1",
+				"kind":       "Subnet",
+				"metadata":   map[string]any{"name": "subnet"},
+				"spec":       map[string]any{"forProvider": map[string]any{"cidrBlock": "10.0.0.0/24"}},
+			},
+		},
+		"ReleasesWhenReady": {
+			observed: map[string]string{"vpc": testVPCReady},
+			want:     testMap(t, testSubnet),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestComposition(t, testRequest(t, "", tc.observed, nil))
+			if err := c.AddDesired("subnet", testObject(t, testSubnet), DependsOnReady("vpc")); err != nil {
+				t.Fatalf("AddDesired(...): unexpected error: %v", err)
+			}
+
+			rsp := &fnv1.RunFunctionResponse{}
+			if err := c.ToResponse(rsp); err != nil {
+				t.Fatalf("ToResponse(...): unexpected error: %v", err)
+			}
+
+			r, ok := rsp.GetDesired().GetResources()["subnet"]
+			if tc.want == nil {
+				if ok {
+					t.Errorf("ToResponse(...): want subnet to be held back, got %v", r.GetResource().AsMap())
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("ToResponse(...): subnet is not desired")
+			}
+
+			if got := r.GetResource().AsMap(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ToResponse(...): subnet = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package composite
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/function-sdk-go/logging"
+	"github.com/crossplane/function-sdk-go/resource"
+)
+
+// diffIgnored holds the fields populated by the API server, Crossplane or
+// providers which are never part of the desired state
+var diffIgnored = []string{
+	"status",
+	"metadata.uid",
+	"metadata.resourceVersion",
+	"metadata.generation",
+	"metadata.creationTimestamp",
+	"metadata.deletionTimestamp",
+	"metadata.deletionGracePeriodSeconds",
+	"metadata.managedFields",
+	"metadata.ownerReferences",
+	"metadata.finalizers",
+	"metadata.selfLink",
+	"metadata.annotations[crossplane.io/composition-resource-name]",
+	"metadata.annotations[crossplane.io/external-name]",
+	"metadata.annotations[crossplane.io/external-create-pending]",
+	"metadata.annotations[crossplane.io/external-create-succeeded]",
+	"metadata.annotations[crossplane.io/external-create-failed]",
+	"metadata.annotations[kubectl.kubernetes.io/last-applied-configuration]",
+	"metadata.labels[crossplane.io/composite]",
+	"metadata.labels[crossplane.io/claim-name]",
+	"metadata.labels[crossplane.io/claim-namespace]",
+}
+
+// ResourceDiff describes how a desired composed resource differs from its
+// observed counterpart
+type ResourceDiff struct {
+	// Name is the pipeline name of the resource
+	Name resource.Name
+
+	// Created is true when the resource has not been observed yet
+	Created bool
+
+	// Added holds the field paths that are desired but not observed
+	Added []string
+
+	// Removed holds the field paths that are observed but not desired. These
+	// are only reported with `DiffRemoved`
+	Removed []string
+
+	// Changed holds the field paths whose desired value differs from the
+	// observed value
+	Changed []string
+}
+
+// String returns a short summary of the diff
+func (d ResourceDiff) String() string {
+	if d.Created {
+		return fmt.Sprintf("%s: created", d.Name)
+	}
+
+	var parts []string
+	if len(d.Added) > 0 {
+		parts = append(parts, "added "+strings.Join(d.Added, ", "))
+	}
+	if len(d.Removed) > 0 {
+		parts = append(parts, "removed "+strings.Join(d.Removed, ", "))
+	}
+	if len(d.Changed) > 0 {
+		parts = append(parts, "changed "+strings.Join(d.Changed, ", "))
+	}
+	return fmt.Sprintf("%s: %s", d.Name, strings.Join(parts, "; "))
+}
+
+// DiffOption configures the behaviour of `Diff`
+type DiffOption func(*diffOptions)
+
+type diffOptions struct {
+	ignore  []string
+	log     logging.Logger
+	result  bool
+	removed bool
+}
+
+// DiffIgnore excludes additional field paths from the diff
+func DiffIgnore(paths ...string) DiffOption {
+	return func(o *diffOptions) {
+		o.ignore = append(o.ignore, paths...)
+	}
+}
+
+// DiffRemoved reports field paths that are observed but not desired as
+// `Removed`
+//
+// These are off by default as providers late initialise most of the fields
+// of a resource, so any existing resource would differ on every run.
+func DiffRemoved() DiffOption {
+	return func(o *diffOptions) {
+		o.removed = true
+	}
+}
+
+// DiffLogger logs every resource that differs
+func DiffLogger(log logging.Logger) DiffOption {
+	return func(o *diffOptions) {
+		o.log = log
+	}
+}
+
+// DiffAsResult records a normal result for every resource that differs
+func DiffAsResult() DiffOption {
+	return func(o *diffOptions) {
+		o.result = true
+	}
+}
+
+// Diff compares each desired composed resource with its observed counterpart
+//
+// Status and metadata populated by the server, Crossplane or providers are
+// ignored. By default only the fields of the desired resource are compared, so
+// a diff lists the fields that are added or changed. Pass `DiffRemoved` to
+// report observed fields that are not desired too. Only resources that differ
+// are returned, sorted by pipeline name.
+//
+// Resources that cannot be compared are skipped and reported together as an
+// `AggregateError`, alongside the diffs of the other resources.
+func (c *TypedComposition[XR, In]) Diff(opts ...DiffOption) (diffs []ResourceDiff, err error) {
+	options := &diffOptions{
+		ignore: slices.Clone(diffIgnored),
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	names := make([]resource.Name, 0, len(c.DesiredComposed))
+	for n := range c.DesiredComposed {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+
+	var errs AggregateError
+	for _, n := range names {
+		d := ResourceDiff{Name: n}
+
+		observed, ok := c.ObservedComposed[n]
+		if !ok {
+			d.Created = true
+			diffs = append(diffs, d)
+			continue
+		}
+
+		var want, got map[string]any
+		if err := To(c.DesiredComposed[n].Resource.Object, &want); err != nil {
+			errs.Append(errors.Wrapf(err, "cannot read desired resource %q", n))
+			continue
+		}
+		if err := To(observed.Resource.Object, &got); err != nil {
+			errs.Append(errors.Wrapf(err, "cannot read observed resource %q", n))
+			continue
+		}
+
+		for _, p := range options.ignore {
+			_ = fieldpath.Pave(want).DeleteField(p)
+			_ = fieldpath.Pave(got).DeleteField(p)
+		}
+
+		diffValues(nil, want, got, options.removed, &d)
+		if len(d.Added)+len(d.Removed)+len(d.Changed) > 0 {
+			diffs = append(diffs, d)
+		}
+	}
+
+	for _, d := range diffs {
+		if options.log != nil {
+			options.log.Info("Composed resource differs from observed state",
+				"resource", d.Name,
+				"created", d.Created,
+				"added", d.Added,
+				"removed", d.Removed,
+				"changed", d.Changed,
+			)
+		}
+
+		if options.result {
+			c.Normal("ComposedResourceDiff", d.String())
+		}
+	}
+
+	err = errs.ErrorOrNil()
+	return
+}
+
+// diffValues records the differences between want and got at path on d
+//
+// Fields only found in got are recorded when removed is set. Otherwise a list
+// that got longer is recorded as changed.
+func diffValues(path fieldpath.Segments, want, got any, removed bool, d *ResourceDiff) {
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(w)+len(g))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		for k := range g {
+			if _, ok := w[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			p := append(slices.Clone(path), fieldpath.Field(k))
+			wv, wok := w[k]
+			gv, gok := g[k]
+			switch {
+			case !gok:
+				d.Added = append(d.Added, p.String())
+			case !wok:
+				if removed {
+					d.Removed = append(d.Removed, p.String())
+				}
+			default:
+				diffValues(p, wv, gv, removed, d)
+			}
+		}
+		return
+	case []any:
+		g, ok := got.([]any)
+		if !ok {
+			break
+		}
+
+		if !removed && len(g) > len(w) {
+			d.Changed = append(d.Changed, path.String())
+			return
+		}
+
+		for i := 0; i < max(len(w), len(g)); i++ {
+			p := append(slices.Clone(path), fieldpath.Segment{Type: fieldpath.SegmentIndex, Index: uint(i)})
+			switch {
+			case i >= len(g):
+				d.Added = append(d.Added, p.String())
+			case i >= len(w):
+				d.Removed = append(d.Removed, p.String())
+			default:
+				diffValues(p, w[i], g[i], removed, d)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		d.Changed = append(d.Changed, path.String())
+	}
+}
@@ -0,0 +1,128 @@
+package composite
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/crossplane/function-sdk-go/resource"
+)
+
+func TestDiff(t *testing.T) {
+	observed := `{
+		"apiVersion": "ec2.aws.upbound.io/v1beta1",
+		"kind": "VPC",
+		"metadata": {
+			"name": "xr-vpc",
+			"uid": "1234",
+			"labels": {"crossplane.io/composite": "xr", "team": "a"},
+			"annotations": {
+				"crossplane.io/composition-resource-name": "vpc",
+				"crossplane.io/external-name": "vpc-123"
+			}
+		},
+		"spec": {
+			"forProvider": {
+				"cidrBlock": "10.0.0.0/16",
+				"region": "eu-west-1",
+				"enableDnsSupport": true,
+				"tags": ["a"]
+			}
+		},
+		"status": {"atProvider": {"id": "vpc-123"}}
+	}`
+
+	cases := map[string]struct {
+		desired string
+		opts    []DiffOption
+		want    []ResourceDiff
+	}{
+		"IgnoresLateInitialisedAndControllerFields": {
+			desired: `{
+				"apiVersion": "ec2.aws.upbound.io/v1beta1",
+				"kind": "VPC",
+				"metadata": {"labels": {"team": "a"}},
+				"spec": {"forProvider": {"cidrBlock": "10.0.0.0/16", "region": "eu-west-1", "tags": ["a"]}}
+			}`,
+		},
+		"ReportsAddedAndChangedFields": {
+			desired: `{
+				"apiVersion": "ec2.aws.upbound.io/v1beta1",
+				"kind": "VPC",
+				"spec": {"forProvider": {"cidrBlock": "10.1.0.0/16", "region": "eu-west-1", "ipv6": true, "tags": []}}
+			}`,
+			want: []ResourceDiff{{
+				Name:    "vpc",
+				Added:   []string{"spec.forProvider.ipv6"},
+				Changed: []string{"spec.forProvider.cidrBlock", "spec.forProvider.tags"},
+			}},
+		},
+		"ReportsRemovedFieldsWhenAsked": {
+			desired: `{
+				"apiVersion": "ec2.aws.upbound.io/v1beta1",
+				"kind": "VPC",
+				"spec": {"forProvider": {"cidrBlock": "10.0.0.0/16", "region": "eu-west-1", "tags": ["a"]}}
+			}`,
+			opts: []DiffOption{DiffRemoved()},
+			want: []ResourceDiff{{
+				Name:    "vpc",
+				Removed: []string{"metadata", "spec.forProvider.enableDnsSupport"},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := testRequest(t, "", map[string]string{"vpc": observed}, map[string]string{"vpc": tc.desired})
+			c := newTestComposition(t, req)
+
+			got, err := c.Diff(tc.opts...)
+			if err != nil {
+				t.Fatalf("Diff(...): unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Diff(...) = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDiffCreated(t *testing.T) {
+	req := testRequest(t, "", nil, map[string]string{"vpc": `{"apiVersion": "v1", "kind": "ConfigMap"}`})
+	c := newTestComposition(t, req)
+
+	want := []ResourceDiff{{Name: "vpc", Created: true}}
+	if got, err := c.Diff(); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, %v, want %+v, nil", got, err, want)
+	}
+}
+
+func TestDiffReportsUnreadableResources(t *testing.T) {
+	vpc := `{"apiVersion": "ec2.aws.upbound.io/v1beta1", "kind": "VPC", "spec": {"forProvider": {"region": "eu-west-1"}}}`
+	req := testRequest(t, "",
+		map[string]string{"a": vpc, "b": vpc, "c": vpc},
+		map[string]string{"a": vpc, "b": vpc, "c": vpc},
+	)
+	c := newTestComposition(t, req)
+
+	// NaN cannot be encoded, so these resources cannot be compared
+	for _, n := range []resource.Name{"a", "c"} {
+		c.DesiredComposed[n].Resource.Object["spec"] = map[string]any{"ratio": math.NaN()}
+	}
+	if err := c.DesiredComposed["b"].Resource.SetValue("spec.forProvider.region", "us-east-1"); err != nil {
+		t.Fatalf("SetValue(...): unexpected error: %v", err)
+	}
+
+	got, err := c.Diff()
+
+	var aggregate *AggregateError
+	if !errors.As(err, &aggregate) || len(aggregate.Errors) != 2 {
+		t.Errorf("Diff() error = %v, want an aggregate of 2 errors", err)
+	}
+
+	want := []ResourceDiff{{Name: "b", Changed: []string{"spec.forProvider.region"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, want %+v", got, want)
+	}
+}
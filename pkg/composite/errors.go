@@ -1,40 +1,412 @@
 package composite
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Sentinel errors matching the errors of this package by category. Use
+// `errors.Is` to test for them.
+var (
+	// ErrMissing matches errors raised when a required object or field does
+	// not exist
+	ErrMissing = errors.New("missing")
+
+	// ErrInvalid matches errors raised when an object, field or input is not
+	// valid
+	ErrInvalid = errors.New("invalid")
+
+	// ErrWaiting matches errors raised when the composition waits on
+	// something that becomes available later. `Run` requeues these with a
+	// short TTL
+	ErrWaiting = errors.New("waiting")
+
+	// ErrConflict matches errors raised when a resource conflicts with one
+	// set earlier in the pipeline
+	ErrConflict = errors.New("conflict")
+)
+
+// SeverityError is an error that knows the severity of the result it should
+// produce
+type SeverityError interface {
+	error
+	Severity() fnv1.Severity
+}
+
+// ErrorSeverity returns the severity of the result `err` should produce
+//
+// Errors that do not implement `SeverityError` anywhere in their chain are
+// fatal.
+func ErrorSeverity(err error) fnv1.Severity {
+	var s SeverityError
+	if errors.As(err, &s) {
+		return s.Severity()
+	}
+	return fnv1.Severity_SEVERITY_FATAL
+}
+
+// WithSeverity overrides the severity of the result `err` produces
+//
+// Example:
+//
+//	// Report the failed lookup but keep the desired state
+//	return composite.WithSeverity(err, fnv1.Severity_SEVERITY_WARNING)
+func WithSeverity(err error, severity fnv1.Severity) error {
+	if err == nil {
+		return nil
+	}
+	return &severityError{err: err, severity: severity}
+}
+
+type severityError struct {
+	err      error
+	severity fnv1.Severity
+}
+
+func (e *severityError) Error() string {
+	return e.err.Error()
+}
+
+func (e *severityError) Unwrap() error {
+	return e.err
+}
+
+func (e *severityError) Severity() fnv1.Severity {
+	return e.severity
+}
+
+// ErrorContext identifies the object and field an error refers to
+//
+// It is embedded in the errors of this package. Fields that are not known are
+// left empty.
+type ErrorContext struct {
+	// GVK of the object
+	GVK schema.GroupVersionKind
+
+	// ObjectName is the name of the object
+	ObjectName string
+
+	// FieldPath is the path of the offending field
+	FieldPath string
+}
+
+// describe returns the context to append to error messages
+func (c ErrorContext) describe() string {
+	var parts []string
+	if !c.GVK.Empty() {
+		parts = append(parts, strings.TrimSpace(c.GVK.GroupVersion().String()+" "+c.GVK.Kind))
+	}
+	if c.ObjectName != "" {
+		parts = append(parts, fmt.Sprintf("%q", c.ObjectName))
+	}
+	if c.FieldPath != "" {
+		parts = append(parts, "field "+c.FieldPath)
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, " ") + ")"
+}
+
+// objectContext builds the error context of an unstructured object
+func objectContext(object map[string]any, fieldPath string) ErrorContext {
+	apiVersion, _ := object["apiVersion"].(string)
+	kind, _ := object["kind"].(string)
+	metadata, _ := object["metadata"].(map[string]any)
+	name, _ := metadata["name"].(string)
+
+	return ErrorContext{
+		GVK:        schema.FromAPIVersionAndKind(apiVersion, kind),
+		ObjectName: name,
+		FieldPath:  fieldPath,
+	}
+}
+
+// missing, invalid, waiting and conflict are embedded in the errors of this
+// package to give them their category and severity
+type (
+	missing  struct{}
+	invalid  struct{}
+	waiting  struct{}
+	conflict struct{}
+)
+
+func (missing) Is(target error) bool     { return target == ErrMissing }
+func (missing) Severity() fnv1.Severity  { return fnv1.Severity_SEVERITY_FATAL }
+func (invalid) Is(target error) bool     { return target == ErrInvalid }
+func (invalid) Severity() fnv1.Severity  { return fnv1.Severity_SEVERITY_FATAL }
+func (waiting) Is(target error) bool     { return target == ErrWaiting }
+func (waiting) Severity() fnv1.Severity  { return fnv1.Severity_SEVERITY_NORMAL }
+func (conflict) Is(target error) bool    { return target == ErrConflict }
+func (conflict) Severity() fnv1.Severity { return fnv1.Severity_SEVERITY_FATAL }
+
+// AggregateError collects several errors raised at once
+//
+// `errors.Is` and `errors.As` match any of the collected errors. The severity
+// is the most severe of the collected errors.
+type AggregateError struct {
+	Errors []error
+}
+
+// Append adds err to the aggregate. Nil errors are ignored and aggregates are
+// flattened
+func (e *AggregateError) Append(err error) {
+	if err == nil {
+		return
+	}
+
+	if aggregate, ok := err.(*AggregateError); ok {
+		e.Errors = append(e.Errors, aggregate.Errors...)
+		return
+	}
+	e.Errors = append(e.Errors, err)
+}
+
+// ErrorOrNil returns nil if no errors were collected, the error itself if
+// only one was collected and the aggregate otherwise
+func (e *AggregateError) ErrorOrNil() error {
+	switch len(e.Errors) {
+	case 0:
+		return nil
+	case 1:
+		return e.Errors[0]
+	}
+	return e
+}
+
+func (e *AggregateError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors occurred: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+func (e *AggregateError) Unwrap() []error {
+	return e.Errors
+}
+
+// Severity returns the most severe severity of the collected errors
+func (e *AggregateError) Severity() (s fnv1.Severity) {
+	s = fnv1.Severity_SEVERITY_NORMAL
+	for _, err := range e.Errors {
+		if es := ErrorSeverity(err); severityRank(es) > severityRank(s) {
+			s = es
+		}
+	}
+	return
+}
+
+// severityRank orders severities from normal to fatal
+func severityRank(s fnv1.Severity) int {
+	switch s {
+	case fnv1.Severity_SEVERITY_NORMAL:
+		return 1
+	case fnv1.Severity_SEVERITY_WARNING:
+		return 2
+	}
+	return 3
+}
+
 // MissingMetadata is raised when an object does not contain a metadata type
-type MissingMetadata struct{}
+type MissingMetadata struct {
+	ErrorContext
+	missing
+}
 
 func (e *MissingMetadata) Error() string {
-	return "object does not contain metadata"
+	return "object does not contain metadata" + e.describe()
 }
 
 // InvalidMetadata is raised when an object metadata cannot be unpacked to a
 // Metadata object
-type InvalidMetadata struct{}
+type InvalidMetadata struct {
+	ErrorContext
+	invalid
+}
 
 func (e *InvalidMetadata) Error() string {
-	return "invalid or empty metadata object"
+	return "invalid or empty metadata object" + e.describe()
 }
 
 // MissingSpec when an object requiring spec is detected but spec is not found
 // during unpack
-type MissingSpec struct{}
+type MissingSpec struct {
+	ErrorContext
+	missing
+}
 
 func (e *MissingSpec) Error() string {
-	return "object does not contain spec field"
+	return "object does not contain spec field" + e.describe()
 }
 
 // InvalidSpec is raised when an object spec cannot be unpacked to the required
 // spec object
-type InvalidSpec struct{}
+type InvalidSpec struct {
+	ErrorContext
+	invalid
+}
 
 func (e *InvalidSpec) Error() string {
-	return "invalid or empty object spec"
+	return "invalid or empty object spec" + e.describe()
 }
 
 // WaitingForSpec Raise this error if your input has no spec on the XR but spec
 // is required. Methods receiving this should return response.Normal
-type WaitingForSpec struct{}
+type WaitingForSpec struct {
+	ErrorContext
+	waiting
+}
 
 func (w *WaitingForSpec) Error() string {
-	return "spec is empty or undefined"
+	return "spec is empty or undefined" + w.describe()
+}
+
+// InvalidStatusPath is raised when a status write targets a field outside of
+// the status object
+type InvalidStatusPath struct {
+	ErrorContext
+	invalid
+	Path string
+}
+
+func (e *InvalidStatusPath) Error() string {
+	return fmt.Sprintf("field path %q is outside of status", e.Path) + e.describe()
+}
+
+// WaitingForExtraResources is raised when extra resources have been required
+// but Crossplane has not supplied them yet. Methods receiving this should
+// return response.Normal
+type WaitingForExtraResources struct {
+	ErrorContext
+	waiting
+	Key string
+}
+
+func (w *WaitingForExtraResources) Error() string {
+	return fmt.Sprintf("extra resources %q are not yet available", w.Key) + w.describe()
+}
+
+// MergeConflict is raised when a desired resource would override values set
+// by an earlier step in the pipeline and conflicts are configured to fail
+type MergeConflict struct {
+	ErrorContext
+	conflict
+	Name  string
+	Paths []string
+}
+
+func (e *MergeConflict) Error() string {
+	return fmt.Sprintf("resource %q overrides fields set earlier in the pipeline: %s", e.Name, strings.Join(e.Paths, ", ")) + e.describe()
+}
+
+// InvalidName is raised when a name does not satisfy the kubernetes naming
+// rules
+type InvalidName struct {
+	ErrorContext
+	invalid
+	Name    string
+	Reasons []string
+}
+
+func (e *InvalidName) Error() string {
+	return fmt.Sprintf("invalid name %q: %s", e.Name, strings.Join(e.Reasons, "; ")) + e.describe()
+}
+
+// MissingResource is raised when a composed resource is neither observed nor
+// desired by any step in the pipeline
+type MissingResource struct {
+	ErrorContext
+	missing
+	Name string
+}
+
+func (e *MissingResource) Error() string {
+	return fmt.Sprintf("composed resource %q is not part of the composition", e.Name) + e.describe()
+}
+
+// WaitingForResource is raised when a composed resource is desired but does not
+// exist yet. Methods receiving this should return response.Normal
+type WaitingForResource struct {
+	ErrorContext
+	waiting
+	Name string
+}
+
+func (w *WaitingForResource) Error() string {
+	return fmt.Sprintf("composed resource %q has not been created yet", w.Name) + w.describe()
+}
+
+// InvalidManifest is raised when a document of a manifest cannot be decoded or
+// is not a valid kubernetes object
+type InvalidManifest struct {
+	ErrorContext
+	invalid
+
+	// Source is the file or template the manifest was read from, if known
+	Source string
+
+	// Index is the position of the document in the manifest, starting at 0
+	Index int
+
+	// Line is the line of the source the error was found at
+	Line int
+
+	// Err is the cause of the error
+	Err error
+}
+
+func (e *InvalidManifest) Error() string {
+	source := ""
+	if e.Source != "" {
+		source = " of " + e.Source
+	}
+	return fmt.Sprintf("invalid document %d at line %d%s: %s", e.Index, e.Line, source, e.Err) + e.describe()
+}
+
+func (e *InvalidManifest) Unwrap() error {
+	return e.Err
+}
+
+// InvalidInput is raised when the function input does not satisfy its schema.
+// All violations found are reported at once
+type InvalidInput struct {
+	ErrorContext
+	invalid
+	Violations []InputViolation
+}
+
+// InputViolation is a single schema violation of the function input
+type InputViolation struct {
+	// Field is the path of the offending field
+	Field string
+
+	// Message describes the violation
+	Message string
+}
+
+func (e *InvalidInput) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return fmt.Sprintf("invalid function input: %s", strings.Join(messages, "; ")) + e.describe()
+}
+
+// UnsupportedInputVersion is raised when the function input is of a version
+// that is not registered with the input registry
+type UnsupportedInputVersion struct {
+	ErrorContext
+	invalid
+	APIVersion string
+	Supported  []string
+}
+
+func (e *UnsupportedInputVersion) Error() string {
+	return fmt.Sprintf("function input %s is not supported, use one of %s", e.APIVersion, strings.Join(e.Supported, ", ")) + e.describe()
 }
@@ -0,0 +1,324 @@
+package composite
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"github.com/crossplane/function-sdk-go/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestErrorCategories(t *testing.T) {
+	categories := []error{ErrMissing, ErrInvalid, ErrWaiting, ErrConflict}
+
+	cases := map[string]struct {
+		err          error
+		want         error
+		wantSeverity fnv1.Severity
+	}{
+		"MissingMetadata": {
+			err:          &MissingMetadata{},
+			want:         ErrMissing,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+		},
+		"InvalidMetadata": {
+			err:          &InvalidMetadata{},
+			want:         ErrInvalid,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+		},
+		"MissingSpec": {
+			err:          &MissingSpec{},
+			want:         ErrMissing,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+		},
+		"InvalidSpec": {
+			err:          &InvalidSpec{},
+			want:         ErrInvalid,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+		},
+		"WaitingForSpec": {
+			err:          &WaitingForSpec{},
+			want:         ErrWaiting,
+			wantSeverity: fnv1.Severity_SEVERITY_NORMAL,
+		},
+		"InvalidStatusPath": {
+			err:          &InvalidStatusPath{Path: "spec.a"},
+			want:         ErrInvalid,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+		},
+		"WaitingForExtraResources": {
+			err:          &WaitingForExtraResources{Key: "a"},
+			want:         ErrWaiting,
+			wantSeverity: fnv1.Severity_SEVERITY_NORMAL,
+		},
+		"MergeConflict": {
+			err:          &MergeConflict{Name: "a", Paths: []string{"spec.a"}},
+			want:         ErrConflict,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+		},
+		"InvalidName": {
+			err:          &InvalidName{Name: "A", Reasons: []string{"uppercase"}},
+			want:         ErrInvalid,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+		},
+		"MissingResource": {
+			err:          &MissingResource{Name: "a"},
+			want:         ErrMissing,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+		},
+		"WaitingForResource": {
+			err:          &WaitingForResource{Name: "a"},
+			want:         ErrWaiting,
+			wantSeverity: fnv1.Severity_SEVERITY_NORMAL,
+		},
+		"InvalidManifest": {
+			err:          &InvalidManifest{Err: errors.New("boom")},
+			want:         ErrInvalid,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+		},
+		"InvalidInput": {
+			err:          &InvalidInput{},
+			want:         ErrInvalid,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+		},
+		"UnsupportedInputVersion": {
+			err:          &UnsupportedInputVersion{APIVersion: "test.xfnlib.io/v2"},
+			want:         ErrInvalid,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+		},
+		"Wrapped": {
+			err:          fmt.Errorf("cannot read: %w", &WaitingForResource{Name: "a"}),
+			want:         ErrWaiting,
+			wantSeverity: fnv1.Severity_SEVERITY_NORMAL,
+		},
+		"Plain": {
+			err:          errors.New("boom"),
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			for _, category := range categories {
+				if got := errors.Is(tc.err, category); got != (category == tc.want) {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", tc.err, category, got, !got)
+				}
+			}
+
+			if got := ErrorSeverity(tc.err); got != tc.wantSeverity {
+				t.Errorf("ErrorSeverity(%v) = %v, want %v", tc.err, got, tc.wantSeverity)
+			}
+		})
+	}
+}
+
+func TestWithSeverity(t *testing.T) {
+	cases := map[string]struct {
+		err          error
+		severity     fnv1.Severity
+		wantSeverity fnv1.Severity
+		wantNil      bool
+	}{
+		"Nil": {
+			severity: fnv1.Severity_SEVERITY_WARNING,
+			wantNil:  true,
+		},
+		"DowngradesFatal": {
+			err:          &MissingResource{Name: "a"},
+			severity:     fnv1.Severity_SEVERITY_WARNING,
+			wantSeverity: fnv1.Severity_SEVERITY_WARNING,
+		},
+		"UpgradesWaiting": {
+			err:          &WaitingForResource{Name: "a"},
+			severity:     fnv1.Severity_SEVERITY_FATAL,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+		},
+		"WrappedAgain": {
+			err:          fmt.Errorf("outer: %w", WithSeverity(errors.New("boom"), fnv1.Severity_SEVERITY_NORMAL)),
+			severity:     fnv1.Severity_SEVERITY_WARNING,
+			wantSeverity: fnv1.Severity_SEVERITY_WARNING,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := WithSeverity(tc.err, tc.severity)
+			if tc.wantNil {
+				if err != nil {
+					t.Errorf("WithSeverity(nil, ...) = %v, want nil", err)
+				}
+				return
+			}
+
+			if got := ErrorSeverity(fmt.Errorf("wrapped: %w", err)); got != tc.wantSeverity {
+				t.Errorf("ErrorSeverity(...) = %v, want %v", got, tc.wantSeverity)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Errorf("WithSeverity(...) = %v, want it to wrap %v", err, tc.err)
+			}
+			if err.Error() != tc.err.Error() {
+				t.Errorf("WithSeverity(...).Error() = %q, want %q", err.Error(), tc.err.Error())
+			}
+		})
+	}
+}
+
+func TestAggregateError(t *testing.T) {
+	missing := &MissingResource{Name: "a"}
+	waiting := &WaitingForResource{Name: "b"}
+	warning := WithSeverity(errors.New("c"), fnv1.Severity_SEVERITY_WARNING)
+
+	cases := map[string]struct {
+		errs         []error
+		wantNil      bool
+		wantCount    int
+		wantSeverity fnv1.Severity
+		wantIs       []error
+	}{
+		"Empty": {
+			errs:    []error{nil, nil},
+			wantNil: true,
+		},
+		"Single": {
+			errs:         []error{nil, waiting},
+			wantSeverity: fnv1.Severity_SEVERITY_NORMAL,
+			wantIs:       []error{ErrWaiting},
+		},
+		"Waiting": {
+			errs:         []error{waiting, &WaitingForExtraResources{Key: "a"}},
+			wantCount:    2,
+			wantSeverity: fnv1.Severity_SEVERITY_NORMAL,
+			wantIs:       []error{ErrWaiting},
+		},
+		"MostSevere": {
+			errs:         []error{waiting, warning, missing},
+			wantCount:    3,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+			wantIs:       []error{ErrWaiting, ErrMissing},
+		},
+		"Warning": {
+			errs:         []error{waiting, warning},
+			wantCount:    2,
+			wantSeverity: fnv1.Severity_SEVERITY_WARNING,
+			wantIs:       []error{ErrWaiting},
+		},
+		"Flattens": {
+			errs:         []error{&AggregateError{Errors: []error{waiting, missing}}, warning},
+			wantCount:    3,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+			wantIs:       []error{ErrWaiting, ErrMissing},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var errs AggregateError
+			for _, err := range tc.errs {
+				errs.Append(err)
+			}
+
+			err := errs.ErrorOrNil()
+			if tc.wantNil {
+				if err != nil {
+					t.Errorf("ErrorOrNil() = %v, want nil", err)
+				}
+				return
+			}
+
+			var aggregate *AggregateError
+			if errors.As(err, &aggregate) {
+				if len(aggregate.Unwrap()) != tc.wantCount {
+					t.Errorf("Unwrap() = %v, want %d errors", aggregate.Unwrap(), tc.wantCount)
+				}
+			} else if tc.wantCount != 0 {
+				t.Errorf("ErrorOrNil() = %v, want *AggregateError", err)
+			}
+
+			if got := ErrorSeverity(err); got != tc.wantSeverity {
+				t.Errorf("ErrorSeverity(...) = %v, want %v", got, tc.wantSeverity)
+			}
+			for _, want := range tc.wantIs {
+				if !errors.Is(err, want) {
+					t.Errorf("errors.Is(%v, %v) = false, want true", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestToUnstructuredAggregatesErrors(t *testing.T) {
+	cases := map[string]struct {
+		object    map[string]any
+		wantCount int
+		wantAs    []any
+	}{
+		"Valid": {
+			object: map[string]any{"metadata": map[string]any{"name": "a"}, "spec": map[string]any{"a": 1}},
+		},
+		"MissingSpec": {
+			object:    map[string]any{"metadata": map[string]any{"name": "a"}},
+			wantCount: 1,
+			wantAs:    []any{new(*InvalidSpec)},
+		},
+		"MissingMetadataAndSpec": {
+			object:    map[string]any{},
+			wantCount: 2,
+			wantAs:    []any{new(*InvalidMetadata), new(*InvalidSpec)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ToUnstructured("test.xfnlib.io/v1", "Test", tc.object)
+			if tc.wantCount == 0 {
+				if err != nil {
+					t.Errorf("ToUnstructured(...): unexpected error: %v", err)
+				}
+				return
+			}
+
+			var aggregate *AggregateError
+			if got := errors.As(err, &aggregate); got != (tc.wantCount > 1) {
+				t.Errorf("ToUnstructured(...): error = %v, want an aggregate %v", err, tc.wantCount > 1)
+			}
+			for _, target := range tc.wantAs {
+				if !errors.As(err, target) {
+					t.Errorf("ToUnstructured(...): error = %v, want %T", err, target)
+				}
+			}
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("ToUnstructured(...): error = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
+
+func TestNewTypedAggregatesErrors(t *testing.T) {
+	type xr struct {
+		Spec struct {
+			Region int `json:"region"`
+		} `json:"spec"`
+	}
+
+	req := testRequest(t, "", nil, nil)
+	req.Input = resource.MustStructJSON(`{"apiVersion": "test.xfnlib.io/v1", "kind": "Input", "spec": {"replicas": 0}}`)
+
+	_, err := NewTyped[xr](req, &unstructured.Unstructured{},
+		WithInputSchema(MustParseInputSchema([]byte(testInputCRD))),
+	)
+
+	var aggregate *AggregateError
+	if !errors.As(err, &aggregate) || len(aggregate.Errors) != 2 {
+		t.Fatalf("NewTyped(...): error = %v, want an aggregate of 2 errors", err)
+	}
+
+	var invalid *InvalidInput
+	if !errors.As(err, &invalid) {
+		t.Errorf("NewTyped(...): error = %v, want *InvalidInput", err)
+	}
+	if got := ErrorSeverity(err); got != fnv1.Severity_SEVERITY_FATAL {
+		t.Errorf("ErrorSeverity(...) = %v, want %v", got, fnv1.Severity_SEVERITY_FATAL)
+	}
+}
@@ -0,0 +1,94 @@
+package composite
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+)
+
+// ResourceMatcher selects which resources of a kind are required
+type ResourceMatcher func(*fnv1.ResourceSelector)
+
+// MatchName requires the resource with the given name
+func MatchName(name string) ResourceMatcher {
+	return func(s *fnv1.ResourceSelector) {
+		s.Match = &fnv1.ResourceSelector_MatchName{
+			MatchName: name,
+		}
+	}
+}
+
+// MatchLabels requires all resources carrying the given labels
+func MatchLabels(labels map[string]string) ResourceMatcher {
+	return func(s *fnv1.ResourceSelector) {
+		s.Match = &fnv1.ResourceSelector_MatchLabels{
+			MatchLabels: &fnv1.MatchLabels{
+				Labels: labels,
+			},
+		}
+	}
+}
+
+// RequireResources asks Crossplane to supply extra resources to the function
+//
+// The requirement is sent with `ToResponse` and Crossplane calls the function
+// again with the matching resources. Use `GetExtraResources` to read them.
+//
+// This replaces the need for a cluster client and the RBAC that comes with it.
+//
+//   - `key` The key the resources are returned under
+//   - `apiVersion` The API version of the required resources
+//   - `kind` The kind of the required resources
+//   - `match` How to select the resources
+//
+// Example:
+//
+//	composed.RequireResources("cluster", "cluster.x-k8s.io/v1beta1", "Cluster", composite.MatchName(name))
+func (c *TypedComposition[XR, In]) RequireResources(key, apiVersion, kind string, match ResourceMatcher) {
+	if c.requirements == nil {
+		c.requirements = make(map[string]*fnv1.ResourceSelector)
+	}
+
+	s := &fnv1.ResourceSelector{
+		ApiVersion: apiVersion,
+		Kind:       kind,
+	}
+	match(s)
+	c.requirements[key] = s
+}
+
+// ExtraResourcesAvailable returns true once Crossplane has supplied the extra
+// resources required under `key`
+//
+// Resources are available even when nothing matched the requirement.
+func (c *TypedComposition[XR, In]) ExtraResourcesAvailable(key string) bool {
+	_, ok := c.extra[key]
+	return ok
+}
+
+// GetExtraResources decodes the extra resources supplied under `key`
+//
+// A `WaitingForExtraResources` error is returned when Crossplane has not yet
+// supplied the resources. This is expected on the first run after a
+// requirement is declared.
+//
+// Example:
+//
+//	clusters, err := composite.GetExtraResources[capiv1.Cluster](composed, "cluster")
+func GetExtraResources[T any, XR any, In InputProvider](c *TypedComposition[XR, In], key string) (items []T, err error) {
+	extra, ok := c.extra[key]
+	if !ok {
+		err = &WaitingForExtraResources{Key: key}
+		return
+	}
+
+	items = make([]T, 0, len(extra))
+	for _, e := range extra {
+		var item T
+		if err = To(e.Resource.Object, &item); err != nil {
+			err = errors.Wrapf(err, "cannot convert extra resource %q to %T", e.Resource.GetName(), item)
+			return
+		}
+		items = append(items, item)
+	}
+	return
+}
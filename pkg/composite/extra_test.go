@@ -0,0 +1,138 @@
+package composite
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"github.com/crossplane/function-sdk-go/resource"
+	"github.com/crossplane/function-sdk-go/response"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRequireResources(t *testing.T) {
+	cases := map[string]struct {
+		match ResourceMatcher
+		want  *fnv1.ResourceSelector
+	}{
+		"Name": {
+			match: MatchName("cluster"),
+			want: &fnv1.ResourceSelector{
+				ApiVersion: "cluster.x-k8s.io/v1beta1",
+				Kind:       "Cluster",
+				Match:      &fnv1.ResourceSelector_MatchName{MatchName: "cluster"},
+			},
+		},
+		"Labels": {
+			match: MatchLabels(map[string]string{"app": "a"}),
+			want: &fnv1.ResourceSelector{
+				ApiVersion: "cluster.x-k8s.io/v1beta1",
+				Kind:       "Cluster",
+				Match: &fnv1.ResourceSelector_MatchLabels{
+					MatchLabels: &fnv1.MatchLabels{Labels: map[string]string{"app": "a"}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := testRequest(t, "", nil, nil)
+			c := newTestComposition(t, req)
+			c.RequireResources("cluster", "cluster.x-k8s.io/v1beta1", "Cluster", tc.match)
+
+			rsp := response.To(req, response.DefaultTTL)
+			if err := c.ToResponse(rsp); err != nil {
+				t.Fatalf("ToResponse(...): unexpected error: %v", err)
+			}
+
+			got := rsp.GetRequirements().GetExtraResources()
+			if len(got) != 1 || !proto.Equal(got["cluster"], tc.want) {
+				t.Errorf("ToResponse(...): requirements = %v, want %v under %q", got, tc.want, "cluster")
+			}
+		})
+	}
+}
+
+func TestGetExtraResources(t *testing.T) {
+	type cluster struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			Paused bool `json:"paused"`
+		} `json:"spec"`
+	}
+
+	cases := map[string]struct {
+		extra         map[string][]string
+		want          []string
+		wantAvailable bool
+		wantErr       bool
+		wantWaiting   bool
+	}{
+		"Supplied": {
+			extra: map[string][]string{"cluster": {
+				`{"apiVersion": "cluster.x-k8s.io/v1beta1", "kind": "Cluster", "metadata": {"name": "a"}}`,
+				`{"apiVersion": "cluster.x-k8s.io/v1beta1", "kind": "Cluster", "metadata": {"name": "b"}}`,
+			}},
+			want:          []string{"a", "b"},
+			wantAvailable: true,
+		},
+		"NothingMatched": {
+			extra:         map[string][]string{"cluster": {}},
+			want:          []string{},
+			wantAvailable: true,
+		},
+		"NotSupplied": {
+			extra:       map[string][]string{"other": {}},
+			wantErr:     true,
+			wantWaiting: true,
+		},
+		"WrongType": {
+			extra: map[string][]string{"cluster": {
+				`{"apiVersion": "cluster.x-k8s.io/v1beta1", "kind": "Cluster", "metadata": {"name": "a"}, "spec": {"paused": "yes"}}`,
+			}},
+			wantAvailable: true,
+			wantErr:       true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := testRequest(t, "", nil, nil)
+			req.ExtraResources = make(map[string]*fnv1.Resources, len(tc.extra))
+			for k, items := range tc.extra {
+				req.ExtraResources[k] = &fnv1.Resources{}
+				for _, i := range items {
+					req.ExtraResources[k].Items = append(req.ExtraResources[k].Items, &fnv1.Resource{Resource: resource.MustStructJSON(i)})
+				}
+			}
+			c := newTestComposition(t, req)
+
+			if got := c.ExtraResourcesAvailable("cluster"); got != tc.wantAvailable {
+				t.Errorf("ExtraResourcesAvailable(...) = %v, want %v", got, tc.wantAvailable)
+			}
+
+			items, err := GetExtraResources[cluster](c, "cluster")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("GetExtraResources(...): error = %v, want error %v", err, tc.wantErr)
+			}
+			if got := errors.Is(err, ErrWaiting); got != tc.wantWaiting {
+				t.Errorf("GetExtraResources(...): error = %v, want ErrWaiting %v", err, tc.wantWaiting)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			got := make([]string, 0, len(items))
+			for _, i := range items {
+				got = append(got, i.Metadata.Name)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("GetExtraResources(...) = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -2,14 +2,19 @@ package composite
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // ToUnstructured is a helper function that creates an unstructured object from
 // any object that contains metadata, spec and optionally status.
+//
+// When both metadata and spec are invalid, both errors are returned at once as
+// an `AggregateError`.
 func ToUnstructured(apiVersion, kind, object any) (u *unstructured.Unstructured, err error) {
 	u = &unstructured.Unstructured{}
 	type objS struct {
@@ -22,13 +27,23 @@ func ToUnstructured(apiVersion, kind, object any) (u *unstructured.Unstructured,
 		return
 	}
 
+	gvk := schema.FromAPIVersionAndKind(fmt.Sprint(apiVersion), fmt.Sprint(kind))
+	name, _ := o.Metadata["name"].(string)
+
+	var errs AggregateError
 	if len(o.Metadata) == 0 {
-		err = &InvalidMetadata{}
-		return
+		errs.Append(&InvalidMetadata{
+			ErrorContext: ErrorContext{GVK: gvk, FieldPath: "metadata"},
+		})
 	}
 
 	if len(o.Spec) == 0 {
-		err = &InvalidSpec{}
+		errs.Append(&InvalidSpec{
+			ErrorContext: ErrorContext{GVK: gvk, ObjectName: name, FieldPath: "spec"},
+		})
+	}
+
+	if err = errs.ErrorOrNil(); err != nil {
 		return
 	}
 
@@ -58,7 +73,9 @@ func ToUnstructuredKubernetesObject(mr any, providerConfigRef, deletionPolicy st
 	}
 
 	if _, ok := ud["metadata"]; !ok {
-		err = errors.Wrap(&MissingMetadata{}, "unable to create kubernetes object")
+		err = errors.Wrap(&MissingMetadata{
+			ErrorContext: objectContext(ud, "metadata"),
+		}, "unable to create kubernetes object")
 		return
 	}
 
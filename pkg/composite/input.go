@@ -0,0 +1,272 @@
+package composite
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"github.com/crossplane/function-sdk-go/request"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	openapierrors "k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+	"sigs.k8s.io/yaml"
+)
+
+// InputSchema is an OpenAPI v3 schema the function input is defaulted and
+// validated against
+type InputSchema struct {
+	// versions holds the schema of each version of an input CRD by
+	// apiVersion
+	versions map[string]*spec.Schema
+
+	// schema is used for every input when the schema was not read from a CRD
+	schema *spec.Schema
+}
+
+// ParseInputSchema parses the schema of the function input
+//
+// `b` is either the YAML or JSON of the input CRD, in which case the
+// `openAPIV3Schema` of each version is used for inputs of that version, or a
+// bare OpenAPI v3 schema used for every input.
+//
+// Example:
+//
+//	//go:embed package/input/template.fn.giantswarm.io_inputs.yaml
+//	var inputCRD []byte
+//
+//	var inputSchema = composite.MustParseInputSchema(inputCRD)
+//
+//	composed, err := composite.NewTyped[v1beta1.XCluster](req, &input,
+//		composite.WithInputSchema(inputSchema),
+//	)
+func ParseInputSchema(b []byte) (s *InputSchema, err error) {
+	var object struct {
+		Kind string `json:"kind"`
+	}
+	if err = yaml.Unmarshal(b, &object); err != nil {
+		err = errors.Wrap(err, "cannot decode input schema")
+		return
+	}
+
+	s = &InputSchema{}
+	if object.Kind != "CustomResourceDefinition" {
+		s.schema = &spec.Schema{}
+		if err = yaml.Unmarshal(b, s.schema); err != nil {
+			err = errors.Wrap(err, "cannot decode input schema")
+		}
+		return
+	}
+
+	var crd apiextensionsv1.CustomResourceDefinition
+	if err = yaml.Unmarshal(b, &crd); err != nil {
+		err = errors.Wrap(err, "cannot decode input CRD")
+		return
+	}
+
+	s.versions = make(map[string]*spec.Schema, len(crd.Spec.Versions))
+	for _, v := range crd.Spec.Versions {
+		if v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+
+		schema := &spec.Schema{}
+		if err = To(v.Schema.OpenAPIV3Schema, schema); err != nil {
+			err = errors.Wrapf(err, "cannot convert schema of version %q", v.Name)
+			return
+		}
+		s.versions[crd.Spec.Group+"/"+v.Name] = schema
+	}
+
+	if len(s.versions) == 0 {
+		err = errors.Errorf("input CRD %q has no openAPIV3Schema", crd.GetName())
+	}
+	return
+}
+
+// MustParseInputSchema parses the schema of the function input and panics if
+// it is invalid
+//
+// Use this to initialise package level variables from embedded files.
+func MustParseInputSchema(b []byte) *InputSchema {
+	s, err := ParseInputSchema(b)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// WithInputSchema defaults and validates the function input against `s`
+// before it is decoded into the input struct
+//
+// Defaults declared in the schema are set on fields missing from the input.
+// The input is then validated and all violations are returned at once as an
+// `InvalidInput` error.
+func WithInputSchema(s *InputSchema) Option {
+	return func(o *options) {
+		o.inputSchema = s
+	}
+}
+
+// Default sets the defaults declared in the schema on fields missing from
+// `input`
+func (s *InputSchema) Default(input map[string]any) {
+	if schema := s.schemaFor(input); schema != nil {
+		applyDefaults(input, schema)
+	}
+}
+
+// Validate checks `input` against the schema
+//
+// All violations are returned at once as an `InvalidInput` error.
+func (s *InputSchema) Validate(input map[string]any) (err error) {
+	schema := s.schemaFor(input)
+	if schema == nil {
+		apiVersion, _ := input["apiVersion"].(string)
+		return &InvalidInput{
+			ErrorContext: objectContext(input, ""),
+			Violations: []InputViolation{{
+				Field:   "apiVersion",
+				Message: "unsupported input version " + apiVersion,
+			}},
+		}
+	}
+
+	result := validate.NewSchemaValidator(schema, nil, "", strfmt.Default).Validate(input)
+	if result.IsValid() {
+		return
+	}
+
+	inputErr := &InvalidInput{
+		ErrorContext: objectContext(input, ""),
+	}
+	for _, e := range result.Errors {
+		inputErr.add(e)
+	}
+
+	slices.SortFunc(inputErr.Violations, func(a, b InputViolation) int {
+		return strings.Compare(a.Field+a.Message, b.Field+b.Message)
+	})
+	inputErr.Violations = slices.Compact(inputErr.Violations)
+	return inputErr
+}
+
+// schemaFor returns the schema matching the apiVersion of input
+func (s *InputSchema) schemaFor(input map[string]any) *spec.Schema {
+	if s.versions == nil {
+		return s.schema
+	}
+
+	apiVersion, _ := input["apiVersion"].(string)
+	return s.versions[apiVersion]
+}
+
+// add records the violations held by err
+func (e *InvalidInput) add(err error) {
+	var ce *openapierrors.CompositeError
+	if errors.As(err, &ce) {
+		for _, c := range ce.Errors {
+			e.add(c)
+		}
+		return
+	}
+
+	violation := InputViolation{
+		Message: strings.Replace(err.Error(), " in body", "", 1),
+	}
+
+	var v *openapierrors.Validation
+	if errors.As(err, &v) {
+		violation.Field = v.Name
+	}
+	e.Violations = append(e.Violations, violation)
+}
+
+// applyDefaults sets the defaults declared in schema on value
+func applyDefaults(value any, schema *spec.Schema) {
+	switch v := value.(type) {
+	case map[string]any:
+		for name, property := range schema.Properties {
+			if _, ok := v[name]; !ok && property.Default != nil {
+				v[name] = runtime.DeepCopyJSONValue(normaliseDefault(property.Default))
+			}
+
+			if field, ok := v[name]; ok {
+				applyDefaults(field, &property)
+			}
+		}
+
+		if schema.AdditionalProperties == nil || schema.AdditionalProperties.Schema == nil {
+			return
+		}
+
+		for name, field := range v {
+			if _, ok := schema.Properties[name]; !ok {
+				applyDefaults(field, schema.AdditionalProperties.Schema)
+			}
+		}
+	case []any:
+		if schema.Items == nil || schema.Items.Schema == nil {
+			return
+		}
+
+		for _, item := range v {
+			applyDefaults(item, schema.Items.Schema)
+		}
+	}
+}
+
+// normaliseDefault converts a default value to plain JSON values
+func normaliseDefault(v any) any {
+	var out any
+	if b, err := json.Marshal(v); err == nil && json.Unmarshal(b, &out) == nil {
+		return out
+	}
+	return v
+}
+
+// readInput reads the function input from the request into the input struct,
+// converting older versions and applying the input schema if configured
+//
+// Older versions are converted first, so the schema only needs to describe the
+// hub version.
+func (c *TypedComposition[XR, In]) readInput(req *fnv1.RunFunctionRequest, o *options) (err error) {
+	if o.inputSchema == nil && o.inputRegistry == nil {
+		return request.GetInput(req, c.Input)
+	}
+
+	input := req.GetInput().AsMap()
+
+	var converted bool
+	if converted, err = c.convertInput(input, o); err != nil {
+		return
+	}
+
+	if o.inputSchema == nil {
+		if !converted {
+			err = errors.Wrapf(To(input, c.Input), "cannot get function input %T from %T", c.Input, req)
+		}
+		return
+	}
+
+	if converted {
+		// Validate the hub form the input was converted to
+		input = nil
+		if err = To(c.Input, &input); err != nil {
+			err = errors.Wrapf(err, "cannot read converted function input %T", c.Input)
+			return
+		}
+	}
+
+	o.inputSchema.Default(input)
+	if err = o.inputSchema.Validate(input); err != nil {
+		return
+	}
+
+	err = errors.Wrapf(To(input, c.Input), "cannot get function input %T from %T", c.Input, req)
+	return
+}
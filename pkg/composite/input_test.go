@@ -0,0 +1,299 @@
+package composite
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/crossplane/function-sdk-go/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// testInputCRD is the CRD of the test function input
+const testInputCRD = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: inputs.test.xfnlib.io
+spec:
+  group: test.xfnlib.io
+  names:
+    kind: Input
+    plural: inputs
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          apiVersion:
+            type: string
+          kind:
+            type: string
+          spec:
+            type: object
+            default: {}
+            required:
+            - name
+            properties:
+              name:
+                type: string
+              region:
+                type: string
+                default: eu-west-1
+              replicas:
+                type: integer
+                minimum: 1
+                default: 3
+              tags:
+                type: object
+                additionalProperties:
+                  type: object
+                  properties:
+                    value:
+                      type: string
+                      default: none
+`
+
+func TestInputSchemaDefault(t *testing.T) {
+	schema := MustParseInputSchema([]byte(testInputCRD))
+
+	cases := map[string]struct {
+		input string
+		want  string
+	}{
+		"SetsNestedDefaults": {
+			input: `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input"}`,
+			want: `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input",
+				"spec": {"region": "eu-west-1", "replicas": 3}}`,
+		},
+		"KeepsSetFields": {
+			input: `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input", "spec": {"region": "us-east-1", "replicas": 1}}`,
+			want:  `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input", "spec": {"region": "us-east-1", "replicas": 1}}`,
+		},
+		"DefaultsAdditionalProperties": {
+			input: `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input", "spec": {"tags": {"owner": {}}}}`,
+			want: `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input",
+				"spec": {"region": "eu-west-1", "replicas": 3, "tags": {"owner": {"value": "none"}}}}`,
+		},
+		"UnknownVersion": {
+			input: `{"apiVersion": "test.xfnlib.io/v2", "kind": "Input"}`,
+			want:  `{"apiVersion": "test.xfnlib.io/v2", "kind": "Input"}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			input := testMap(t, tc.input)
+			schema.Default(input)
+
+			if want := testMap(t, tc.want); !reflect.DeepEqual(input, want) {
+				t.Errorf("Default(...) = %v, want %v", input, want)
+			}
+		})
+	}
+}
+
+func TestInputSchemaValidate(t *testing.T) {
+	schema := MustParseInputSchema([]byte(testInputCRD))
+
+	cases := map[string]struct {
+		input      string
+		wantFields []string
+	}{
+		"Valid": {
+			input: `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input", "spec": {"name": "a", "replicas": 2}}`,
+		},
+		"AllViolations": {
+			input:      `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input", "spec": {"region": 1, "replicas": 0}}`,
+			wantFields: []string{"spec.name", "spec.region", "spec.replicas"},
+		},
+		"UnknownVersion": {
+			input:      `{"apiVersion": "test.xfnlib.io/v2", "kind": "Input"}`,
+			wantFields: []string{"apiVersion"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := schema.Validate(testMap(t, tc.input))
+			if tc.wantFields == nil {
+				if err != nil {
+					t.Errorf("Validate(...): unexpected error: %v", err)
+				}
+				return
+			}
+
+			var invalid *InvalidInput
+			if !errors.As(err, &invalid) {
+				t.Fatalf("Validate(...): error = %v, want *InvalidInput", err)
+			}
+
+			var fields []string
+			for _, v := range invalid.Violations {
+				fields = append(fields, v.Field)
+			}
+			if !reflect.DeepEqual(fields, tc.wantFields) {
+				t.Errorf("Validate(...): violations = %+v, want fields %v", invalid.Violations, tc.wantFields)
+			}
+		})
+	}
+}
+
+func TestParseInputSchema(t *testing.T) {
+	cases := map[string]struct {
+		schema  string
+		wantErr bool
+	}{
+		"CRD": {
+			schema: testInputCRD,
+		},
+		"BareSchema": {
+			schema: `{"type": "object", "properties": {"spec": {"type": "object"}}}`,
+		},
+		"CRDWithoutSchema": {
+			schema: `{
+				"apiVersion": "apiextensions.k8s.io/v1",
+				"kind": "CustomResourceDefinition",
+				"metadata": {"name": "inputs.test.xfnlib.io"},
+				"spec": {"group": "test.xfnlib.io", "versions": [{"name": "v1"}]}
+			}`,
+			wantErr: true,
+		},
+		"Invalid": {
+			schema:  `kind: [`,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseInputSchema([]byte(tc.schema)); (err != nil) != tc.wantErr {
+				t.Errorf("ParseInputSchema(...): error = %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewTypedWithInputSchema(t *testing.T) {
+	cases := map[string]struct {
+		input   string
+		want    map[string]any
+		wantErr bool
+	}{
+		"DefaultsInput": {
+			input: `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input", "spec": {"name": "a"}}`,
+			want:  map[string]any{"name": "a", "region": "eu-west-1", "replicas": int64(3)},
+		},
+		"RejectsInvalidInput": {
+			input:   `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input", "spec": {"replicas": 0}}`,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := testRequest(t, "", nil, nil)
+			req.Input = resource.MustStructJSON(tc.input)
+
+			c, err := NewTyped[map[string]any](req, &unstructured.Unstructured{},
+				WithInputSchema(MustParseInputSchema([]byte(testInputCRD))),
+			)
+			if tc.wantErr {
+				var invalid *InvalidInput
+				if !errors.As(err, &invalid) {
+					t.Errorf("NewTyped(...): error = %v, want *InvalidInput", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewTyped(...): unexpected error: %v", err)
+			}
+
+			if got := c.Input.Object["spec"]; !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("NewTyped(...): input spec = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// testInputV1alpha1 is an older version of the test function input
+type testInputV1alpha1 struct {
+	Spec struct {
+		Title string `json:"title"`
+	} `json:"spec"`
+}
+
+func TestNewTypedWithInputSchemaAndRegistry(t *testing.T) {
+	registry := NewInputRegistry("test.xfnlib.io/v1")
+	RegisterInputVersion(registry, "test.xfnlib.io/v1alpha1",
+		func(from *testInputV1alpha1, to *unstructured.Unstructured) error {
+			spec := map[string]any{}
+			if from.Spec.Title != "" {
+				spec["name"] = from.Spec.Title
+			}
+			to.Object = map[string]any{"apiVersion": "test.xfnlib.io/v1", "kind": "Input", "spec": spec}
+			return nil
+		},
+	)
+
+	cases := map[string]struct {
+		input   string
+		want    map[string]any
+		wantErr any
+	}{
+		"Hub": {
+			input: `{"apiVersion": "test.xfnlib.io/v1", "kind": "Input", "spec": {"name": "a"}}`,
+			want:  map[string]any{"name": "a", "region": "eu-west-1", "replicas": int64(3)},
+		},
+		"ConvertsBeforeValidating": {
+			input: `{"apiVersion": "test.xfnlib.io/v1alpha1", "kind": "Input", "spec": {"title": "a"}}`,
+			want:  map[string]any{"name": "a", "region": "eu-west-1", "replicas": int64(3)},
+		},
+		"ValidatesConvertedInput": {
+			input:   `{"apiVersion": "test.xfnlib.io/v1alpha1", "kind": "Input", "spec": {}}`,
+			wantErr: &InvalidInput{},
+		},
+		"UnregisteredVersion": {
+			input:   `{"apiVersion": "test.xfnlib.io/v2", "kind": "Input", "spec": {"name": "a"}}`,
+			wantErr: &UnsupportedInputVersion{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := testRequest(t, "", nil, nil)
+			req.Input = resource.MustStructJSON(tc.input)
+
+			c, err := NewTyped[map[string]any](req, &unstructured.Unstructured{},
+				WithInputSchema(MustParseInputSchema([]byte(testInputCRD))),
+				WithInputRegistry(registry),
+			)
+			switch want := tc.wantErr.(type) {
+			case *InvalidInput:
+				if !errors.As(err, &want) {
+					t.Errorf("NewTyped(...): error = %v, want *InvalidInput", err)
+				}
+				return
+			case *UnsupportedInputVersion:
+				if !errors.As(err, &want) {
+					t.Errorf("NewTyped(...): error = %v, want *UnsupportedInputVersion", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewTyped(...): unexpected error: %v", err)
+			}
+
+			if got := c.Input.GetAPIVersion(); got != "test.xfnlib.io/v1" {
+				t.Errorf("NewTyped(...): input apiVersion = %q, want the hub", got)
+			}
+			if got := c.Input.Object["spec"]; !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("NewTyped(...): input spec = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package composite
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/function-sdk-go/resource"
+	"github.com/crossplane/function-sdk-go/resource/composed"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Phase is the lifecycle phase of the composite resource
+type Phase string
+
+const (
+	// PhaseCreating composites are not ready yet
+	PhaseCreating Phase = "Creating"
+
+	// PhaseReady composites have a Ready condition that is true
+	PhaseReady Phase = "Ready"
+
+	// PhaseDeleting composites have a deletion timestamp
+	PhaseDeleting Phase = "Deleting"
+)
+
+// Phase returns the lifecycle phase of the observed composite resource
+//
+// A composite with a `deletionTimestamp` is deleting, whatever its
+// conditions. Otherwise it is ready once its Ready condition is true and
+// creating until then.
+func (c *TypedComposition[XR, In]) Phase() Phase {
+	if c.observed == nil || c.observed.Resource == nil {
+		return PhaseCreating
+	}
+
+	if c.observed.Resource.GetDeletionTimestamp() != nil {
+		return PhaseDeleting
+	}
+
+	if c.observed.Resource.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue {
+		return PhaseReady
+	}
+	return PhaseCreating
+}
+
+// OnDelete wraps `handler` so that `hooks` are called instead of it while the
+// composite resource is deleted
+//
+// Hooks run in the order they are given and are meant for cleanup such as
+// releasing reservations. Composed resources are kept as they were observed so
+// that nothing is deleted early or regenerated. Use `WithDeletionOrder` to drop
+// them in a controlled order once every hook succeeded.
+//
+// The hooks share the type parameters of `handler`, so a hook for another
+// composite or input type does not compile.
+//
+// Example:
+//
+//	return composite.Run(ctx, req, &v1beta1.Input{},
+//		composite.OnDelete(f.compose, f.release),
+//		composite.WithDeletionOrder("apps", "cluster"),
+//	)
+func OnDelete[XR any, In InputProvider](handler Handler[XR, In], hooks ...Handler[XR, In]) Handler[XR, In] {
+	return func(ctx context.Context, c *TypedComposition[XR, In]) error {
+		if c.Phase() != PhaseDeleting {
+			return handler(ctx, c)
+		}
+		return c.runDeleteHooks(ctx, hooks)
+	}
+}
+
+// WithDeletionOrder drops composed resources one after the other while the
+// composite resource is deleted
+//
+// The first resource in `names` is dropped from the desired resources straight
+// away. Each following resource is dropped once the one before it is no
+// longer observed. Observed resources that are not listed are dropped along
+// with the first one. Resources desired by earlier steps in the pipeline are
+// left alone, even when listed.
+//
+// The order is applied by handlers wrapped with `OnDelete`, after every hook
+// succeeded.
+//
+// Example:
+//
+//	// Remove the workloads before the cluster they run on
+//	composite.WithDeletionOrder("apps", "nodepool", "cluster")
+func WithDeletionOrder(names ...string) Option {
+	return func(o *options) {
+		o.deletionOrder = names
+	}
+}
+
+// runDeleteHooks calls `hooks` and tears down the composed resources
+//
+// Resources are only dropped when every hook succeeded. Until then they are
+// all kept.
+func (c *TypedComposition[XR, In]) runDeleteHooks(ctx context.Context, hooks []Handler[XR, In]) (err error) {
+	for i, hook := range hooks {
+		if err = hook(ctx, c); err != nil {
+			err = errors.Wrapf(err, "OnDelete hook %d failed", i)
+			c.teardown(nil, false)
+			return
+		}
+	}
+
+	c.teardown(c.deletionOrder, len(c.deletionOrder) > 0)
+	return
+}
+
+// teardown keeps the observed composed resources in the desired state and,
+// when `drop` is set, removes them in `order`
+func (c *TypedComposition[XR, In]) teardown(order []string, drop bool) {
+	listed := make(map[resource.Name]bool, len(order))
+	if drop {
+		// Listed resources are dropped up to the first one still observed.
+		// Resources of earlier steps are never dropped
+		for _, n := range order {
+			if _, ok := c.upstream[resource.Name(n)]; ok {
+				continue
+			}
+			_, observed := c.ObservedComposed[resource.Name(n)]
+			listed[resource.Name(n)] = true
+			if observed {
+				break
+			}
+		}
+
+		for _, n := range order {
+			if _, ok := listed[resource.Name(n)]; !ok {
+				listed[resource.Name(n)] = false
+			}
+		}
+	}
+
+	for n, o := range c.ObservedComposed {
+		dropped, ok := listed[n]
+		if drop && !ok {
+			// Unlisted resources go with the first listed one
+			if _, ok := c.upstream[n]; !ok {
+				delete(c.DesiredComposed, n)
+			}
+			continue
+		}
+
+		if _, ok := c.DesiredComposed[n]; ok || dropped {
+			continue
+		}
+
+		c.DesiredComposed[n] = &resource.DesiredComposed{
+			Resource: keptObject(o.Resource),
+			Ready:    resource.ReadyTrue,
+		}
+	}
+
+	for n, dropped := range listed {
+		if dropped {
+			delete(c.DesiredComposed, n)
+		}
+	}
+}
+
+// keptObject returns the parts of an observed composed resource that are
+// handed back to Crossplane to keep it as it is
+func keptObject(observed *composed.Unstructured) *composed.Unstructured {
+	object := observed.DeepCopy().UnstructuredContent()
+	delete(object, "status")
+
+	metadata, _ := object["metadata"].(map[string]any)
+	kept := make(map[string]any)
+	for _, k := range []string{"name", "namespace", "generateName", "labels", "annotations"} {
+		if v, ok := metadata[k]; ok {
+			kept[k] = v
+		}
+	}
+	object["metadata"] = kept
+
+	return &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: object}}
+}
@@ -0,0 +1,210 @@
+package composite
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"github.com/crossplane/function-sdk-go/resource/composed"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// testDeletingXR is a composite resource that is being deleted
+const testDeletingXR = `{
+	"apiVersion": "test.xfnlib.io/v1",
+	"kind": "XTest",
+	"metadata": {"name": "xr", "deletionTimestamp": "2026-01-01T00:00:00Z"},
+	"status": {"conditions": [{"type": "Ready", "status": "True"}]}
+}`
+
+func TestPhase(t *testing.T) {
+	cases := map[string]struct {
+		xr   string
+		want Phase
+	}{
+		"Creating": {
+			xr:   testXR,
+			want: PhaseCreating,
+		},
+		"NotReady": {
+			xr: `{
+				"apiVersion": "test.xfnlib.io/v1",
+				"kind": "XTest",
+				"metadata": {"name": "xr"},
+				"status": {"conditions": [{"type": "Ready", "status": "False"}]}
+			}`,
+			want: PhaseCreating,
+		},
+		"Ready": {
+			xr: `{
+				"apiVersion": "test.xfnlib.io/v1",
+				"kind": "XTest",
+				"metadata": {"name": "xr"},
+				"status": {"conditions": [{"type": "Ready", "status": "True"}]}
+			}`,
+			want: PhaseReady,
+		},
+		"Deleting": {
+			xr:   testDeletingXR,
+			want: PhaseDeleting,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestComposition(t, testRequest(t, tc.xr, nil, nil))
+			if got := c.Phase(); got != tc.want {
+				t.Errorf("Phase() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunDuringDeletion(t *testing.T) {
+	object := func(n string) string {
+		return `{"apiVersion": "test.xfnlib.io/v1", "kind": "Resource", "metadata": {"name": "` + n + `"}, "status": {"id": "1"}}`
+	}
+
+	cases := map[string]struct {
+		observed map[string]string
+		upstream map[string]string
+		hookErr  error
+		order    []string
+		want     []string
+	}{
+		"KeepsObserved": {
+			observed: map[string]string{"apps": object("apps"), "cluster": object("cluster")},
+			want:     []string{"apps", "cluster"},
+		},
+		"DropsFirstInOrder": {
+			observed: map[string]string{"apps": object("apps"), "cluster": object("cluster"), "other": object("other")},
+			order:    []string{"apps", "cluster"},
+			want:     []string{"cluster"},
+		},
+		"DropsNextOnceGone": {
+			observed: map[string]string{"cluster": object("cluster")},
+			order:    []string{"apps", "cluster"},
+		},
+		"LeavesUpstream": {
+			observed: map[string]string{"apps": object("apps"), "shared": object("shared"), "cluster": object("cluster")},
+			upstream: map[string]string{"shared": `{"apiVersion": "test.xfnlib.io/v1", "kind": "Resource", "metadata": {"name": "shared"}}`},
+			order:    []string{"shared", "apps", "cluster"},
+			want:     []string{"cluster", "shared"},
+		},
+		"KeepsAllWhileHookFails": {
+			observed: map[string]string{"apps": object("apps"), "cluster": object("cluster")},
+			hookErr:  &WaitingForResource{Name: "reservation"},
+			order:    []string{"apps", "cluster"},
+			want:     []string{"apps", "cluster"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var hooked bool
+			hook := func(_ context.Context, _ *testComposition) error {
+				hooked = true
+				return tc.hookErr
+			}
+
+			handler := func(_ context.Context, _ *testComposition) error {
+				t.Error("Run(...): handler called while the composite is deleted")
+				return nil
+			}
+
+			var opts []Option
+			if tc.order != nil {
+				opts = append(opts, WithDeletionOrder(tc.order...))
+			}
+
+			req := testRequest(t, testDeletingXR, tc.observed, tc.upstream)
+			rsp, err := Run(context.Background(), req, &unstructured.Unstructured{}, OnDelete(handler, hook), opts...)
+			if err != nil {
+				t.Fatalf("Run(...): unexpected error: %v", err)
+			}
+			if !hooked {
+				t.Error("Run(...): OnDelete hook not called")
+			}
+
+			var got []string
+			for n, r := range rsp.GetDesired().GetResources() {
+				if _, ok := r.GetResource().AsMap()["status"]; ok {
+					t.Errorf("Run(...): desired %q keeps its observed status", n)
+				}
+				got = append(got, n)
+			}
+			slices.Sort(got)
+
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("Run(...): desired = %v, want %v", got, tc.want)
+			}
+
+			for _, r := range rsp.GetResults() {
+				if r.GetSeverity() == fnv1.Severity_SEVERITY_FATAL {
+					t.Errorf("Run(...): fatal result %q", r.GetMessage())
+				}
+			}
+		})
+	}
+}
+
+func TestOnDeleteCallsHandlerUntilDeleted(t *testing.T) {
+	var called bool
+	handler := func(_ context.Context, _ *testComposition) error {
+		called = true
+		return nil
+	}
+	hook := func(_ context.Context, _ *testComposition) error {
+		t.Error("Run(...): OnDelete hook called while the composite is not deleted")
+		return nil
+	}
+
+	req := testRequest(t, "", nil, nil)
+	if _, err := Run(context.Background(), req, &unstructured.Unstructured{}, OnDelete(handler, hook)); err != nil {
+		t.Fatalf("Run(...): unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("Run(...): handler not called")
+	}
+}
+
+func TestKeptObject(t *testing.T) {
+	observed := &composed.Unstructured{Unstructured: *testObject(t, `{
+		"apiVersion": "test.xfnlib.io/v1",
+		"kind": "Resource",
+		"metadata": {
+			"name": "r",
+			"namespace": "ns",
+			"labels": {"a": "b"},
+			"annotations": {"c": "d"},
+			"uid": "1",
+			"resourceVersion": "2",
+			"managedFields": [{"manager": "crossplane"}]
+		},
+		"spec": {"forProvider": {"region": "eu-west-1"}},
+		"status": {"atProvider": {"id": "1"}}
+	}`)}
+
+	want := map[string]any{
+		"apiVersion": "test.xfnlib.io/v1",
+		"kind":       "Resource",
+		"metadata": map[string]any{
+			"name":        "r",
+			"namespace":   "ns",
+			"labels":      map[string]any{"a": "b"},
+			"annotations": map[string]any{"c": "d"},
+		},
+		"spec": map[string]any{"forProvider": map[string]any{"region": "eu-west-1"}},
+	}
+
+	got := keptObject(observed)
+	if !reflect.DeepEqual(got.Object, want) {
+		t.Errorf("keptObject(...) = %v, want %v", got.Object, want)
+	}
+
+	if _, ok := observed.Object["status"]; !ok {
+		t.Error("keptObject(...): observed object was modified")
+	}
+}
@@ -0,0 +1,312 @@
+package composite
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"regexp"
+	"strconv"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// yamlErrorLine finds the line reported in YAML decoding errors
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// Manifest is a single object read from a multi document manifest
+type Manifest struct {
+	// Name is the pipeline name the object is added under
+	Name string
+
+	// Object is the decoded object
+	Object *unstructured.Unstructured
+
+	// Source is the file or template the object was read from, if known
+	Source string
+
+	// Index is the position of the document in the source, starting at 0
+	Index int
+
+	// Line is the first line of the document in the source
+	Line int
+}
+
+// ManifestOption configures how manifests are read
+type ManifestOption func(*manifestOptions)
+
+type manifestOptions struct {
+	source            string
+	nameAnnotation    string
+	generatedNames    bool
+	kubernetesObject  bool
+	providerConfigRef string
+	deletionPolicy    string
+}
+
+// WithManifestSource names the source of the manifest in errors. `ReadManifestsFS`
+// sets this to the file name
+func WithManifestSource(source string) ManifestOption {
+	return func(o *manifestOptions) {
+		o.source = source
+	}
+}
+
+// WithNameAnnotation takes the pipeline name of each object from the
+// annotation `key`. The annotation must be set and is removed from the object.
+// By default `metadata.name` is used as the pipeline name
+func WithNameAnnotation(key string) ManifestOption {
+	return func(o *manifestOptions) {
+		o.nameAnnotation = key
+	}
+}
+
+// WithGeneratedNames allows objects without `metadata.name`, leaving it to
+// Crossplane to generate the name. This requires `WithNameAnnotation`
+func WithGeneratedNames() ManifestOption {
+	return func(o *manifestOptions) {
+		o.generatedNames = true
+	}
+}
+
+// AsKubernetesObjects wraps every object in a provider-kubernetes Object with
+// `ToUnstructuredKubernetesObject`
+//
+// The Object and its connection secret are named after the wrapped object, so
+// `metadata.name` must be set even with `WithGeneratedNames`.
+func AsKubernetesObjects(providerConfigRef, deletionPolicy string) ManifestOption {
+	return func(o *manifestOptions) {
+		o.kubernetesObject = true
+		o.providerConfigRef = providerConfigRef
+		o.deletionPolicy = deletionPolicy
+	}
+}
+
+// ParseManifests decodes a multi document YAML or JSON manifest
+//
+// YAML documents are separated by `---` and JSON documents are concatenated
+// objects. Empty documents are skipped. Every object must set `apiVersion`,
+// `kind` and `metadata.name`. Failures are returned as `InvalidManifest`
+// errors holding the document index and source line.
+//
+// Example:
+//
+//	//go:embed manifests/rbac.yaml
+//	var rbac []byte
+//
+//	manifests, err := composite.ParseManifests(rbac,
+//		composite.AsKubernetesObjects("default", "Delete"),
+//	)
+func ParseManifests(b []byte, opts ...ManifestOption) (manifests []Manifest, err error) {
+	options := &manifestOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var docs []document
+	if docs, err = options.split(b); err != nil {
+		return
+	}
+
+	for _, d := range docs {
+		var m *Manifest
+		if m, err = options.decode(d); err != nil {
+			manifests = nil
+			return
+		}
+
+		if m != nil {
+			manifests = append(manifests, *m)
+		}
+	}
+	return
+}
+
+// ReadManifests decodes a multi document YAML or JSON manifest from `r`
+//
+// See `ParseManifests` for details.
+func ReadManifests(r io.Reader, opts ...ManifestOption) (manifests []Manifest, err error) {
+	var b []byte
+	if b, err = io.ReadAll(r); err != nil {
+		err = errors.Wrap(err, "cannot read manifest")
+		return
+	}
+	return ParseManifests(b, opts...)
+}
+
+// ReadManifestsFS decodes the manifests in the files of `fsys` matching
+// `pattern`, for example an `embed.FS`
+//
+// Files are read in lexical order. See `ParseManifests` for details.
+func ReadManifestsFS(fsys fs.FS, pattern string, opts ...ManifestOption) (manifests []Manifest, err error) {
+	var files []string
+	if files, err = fs.Glob(fsys, pattern); err != nil {
+		err = errors.Wrapf(err, "cannot match %q", pattern)
+		return
+	}
+
+	for _, f := range files {
+		var b []byte
+		if b, err = fs.ReadFile(fsys, f); err != nil {
+			err = errors.Wrapf(err, "cannot read manifest %q", f)
+			return
+		}
+
+		var m []Manifest
+		if m, err = ParseManifests(b, append(opts, WithManifestSource(f))...); err != nil {
+			manifests = nil
+			return
+		}
+		manifests = append(manifests, m...)
+	}
+	return
+}
+
+// AddManifests adds every manifest to the desired composed resources with
+// `AddDesired`
+//
+//   - `manifests` The manifests returned by one of the `ReadManifests`
+//     functions
+//   - `opts` Options passed to `AddDesired` for every manifest
+func (c *TypedComposition[XR, In]) AddManifests(manifests []Manifest, opts ...DesiredOption) (err error) {
+	for _, m := range manifests {
+		if err = c.AddDesired(m.Name, m.Object, opts...); err != nil {
+			err = &InvalidManifest{Source: m.Source, Index: m.Index, Line: m.Line, Err: err}
+			return
+		}
+	}
+	return
+}
+
+// document is a single document of a manifest
+type document struct {
+	data  []byte
+	index int
+	line  int
+}
+
+// decode decodes and validates a single document. Empty documents return nil
+func (o *manifestOptions) decode(d document) (m *Manifest, err error) {
+	var object map[string]any
+	fail := func(line int, field string, err error) (*Manifest, error) {
+		return nil, &InvalidManifest{
+			ErrorContext: objectContext(object, field),
+			Source:       o.source,
+			Index:        d.index,
+			Line:         line,
+			Err:          err,
+		}
+	}
+
+	if err = yaml.Unmarshal(d.data, &object); err != nil {
+		line := d.line
+		if match := yamlErrorLine.FindStringSubmatch(err.Error()); match != nil {
+			n, _ := strconv.Atoi(match[1])
+			line += n - 1
+		}
+		return fail(line, "", err)
+	}
+
+	if len(object) == 0 {
+		return
+	}
+
+	u := &unstructured.Unstructured{Object: object}
+	switch {
+	case u.GetAPIVersion() == "":
+		return fail(d.line, "apiVersion", errors.New("apiVersion is not set"))
+	case u.GetKind() == "":
+		return fail(d.line, "kind", errors.New("kind is not set"))
+	case u.GetName() == "" && !o.generatedNames:
+		return fail(d.line, "metadata.name", errors.New("metadata.name is not set"))
+	}
+
+	name := u.GetName()
+	if o.nameAnnotation != "" {
+		annotations := u.GetAnnotations()
+		if name = annotations[o.nameAnnotation]; name == "" {
+			return fail(d.line, "metadata.annotations["+o.nameAnnotation+"]", errors.Errorf("annotation %s is not set", o.nameAnnotation))
+		}
+
+		delete(annotations, o.nameAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		u.SetAnnotations(annotations)
+	}
+
+	if name == "" {
+		return fail(d.line, "metadata.name", errors.New("object has no pipeline name"))
+	}
+
+	if o.kubernetesObject {
+		if u.GetName() == "" {
+			return fail(d.line, "metadata.name", errors.New("metadata.name is required to name the provider-kubernetes Object"))
+		}
+		if u, err = ToUnstructuredKubernetesObject(u.Object, o.providerConfigRef, o.deletionPolicy); err != nil {
+			return fail(d.line, "", err)
+		}
+	}
+
+	m = &Manifest{
+		Name:   name,
+		Object: u,
+		Source: o.source,
+		Index:  d.index,
+		Line:   d.line,
+	}
+	return
+}
+
+// split splits b into documents, recording the line each document starts at
+func (o *manifestOptions) split(b []byte) (docs []document, err error) {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '{' {
+		return o.splitJSON(b)
+	}
+
+	current := document{line: 1}
+	var buf bytes.Buffer
+	for i, line := range bytes.Split(b, []byte("\n")) {
+		if string(bytes.TrimRight(line, " \t\r")) == "---" {
+			current.data = bytes.Clone(buf.Bytes())
+			docs = append(docs, current)
+			current = document{index: current.index + 1, line: i + 2}
+			buf.Reset()
+			continue
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	current.data = buf.Bytes()
+	docs = append(docs, current)
+	return
+}
+
+// splitJSON splits a stream of concatenated JSON objects into documents
+func (o *manifestOptions) splitJSON(b []byte) (docs []document, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	for i := 0; ; i++ {
+		offset := decoder.InputOffset()
+		offset += int64(len(b[offset:]) - len(bytes.TrimLeft(b[offset:], " \t\r\n")))
+		line := bytes.Count(b[:offset], []byte("\n")) + 1
+
+		var raw json.RawMessage
+		if err = decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				err = nil
+				return
+			}
+			err = &InvalidManifest{Source: o.source, Index: i, Line: line, Err: err}
+			return
+		}
+
+		docs = append(docs, document{
+			data:  raw,
+			index: i,
+			line:  line,
+		})
+	}
+}
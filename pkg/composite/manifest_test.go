@@ -0,0 +1,247 @@
+package composite
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/crossplane/function-sdk-go/resource"
+)
+
+func TestParseManifests(t *testing.T) {
+	type manifest struct {
+		Name  string
+		Kind  string
+		Index int
+		Line  int
+	}
+
+	cases := map[string]struct {
+		manifest string
+		opts     []ManifestOption
+		want     []manifest
+		wantErr  *InvalidManifest
+	}{
+		"YAMLDocuments": {
+			manifest: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+---
+
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: b
+`,
+			want: []manifest{
+				{Name: "a", Kind: "ConfigMap", Index: 1, Line: 2},
+				{Name: "b", Kind: "Secret", Index: 3, Line: 9},
+			},
+		},
+		"JSONStream": {
+			manifest: `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "a"}}
+
+{
+  "apiVersion": "v1",
+  "kind": "Secret",
+  "metadata": {"name": "b"}
+}`,
+			want: []manifest{
+				{Name: "a", Kind: "ConfigMap", Index: 0, Line: 1},
+				{Name: "b", Kind: "Secret", Index: 1, Line: 3},
+			},
+		},
+		"NameAnnotation": {
+			manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  generateName: a-
+  annotations:
+    xfnlib.io/name: config
+`,
+			opts: []ManifestOption{WithNameAnnotation("xfnlib.io/name"), WithGeneratedNames()},
+			want: []manifest{{Name: "config", Kind: "ConfigMap", Line: 1}},
+		},
+		"KubernetesObjects": {
+			manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+`,
+			opts: []ManifestOption{AsKubernetesObjects("default", "Delete")},
+			want: []manifest{{Name: "a", Kind: "Object", Line: 1}},
+		},
+		"KubernetesObjectsGeneratedName": {
+			manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  generateName: a-
+  annotations:
+    xfnlib.io/name: config
+`,
+			opts: []ManifestOption{
+				WithNameAnnotation("xfnlib.io/name"),
+				WithGeneratedNames(),
+				AsKubernetesObjects("default", "Delete"),
+			},
+			wantErr: &InvalidManifest{Index: 0, Line: 1},
+		},
+		"MissingKind": {
+			manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+---
+apiVersion: v1
+metadata:
+  name: b
+`,
+			opts:    []ManifestOption{WithManifestSource("rbac.yaml")},
+			wantErr: &InvalidManifest{Source: "rbac.yaml", Index: 1, Line: 6},
+		},
+		"MissingName": {
+			manifest: `apiVersion: v1
+kind: ConfigMap
+`,
+			wantErr: &InvalidManifest{Index: 0, Line: 1},
+		},
+		"MissingNameAnnotation": {
+			manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+`,
+			opts:    []ManifestOption{WithNameAnnotation("xfnlib.io/name")},
+			wantErr: &InvalidManifest{Index: 0, Line: 1},
+		},
+		"InvalidYAMLLine": {
+			manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b: c
+`,
+			wantErr: &InvalidManifest{Index: 1, Line: 9},
+		},
+		"InvalidJSON": {
+			manifest: `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "a"}}
+{"apiVersion": "v1",`,
+			wantErr: &InvalidManifest{Index: 1, Line: 2},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			manifests, err := ParseManifests([]byte(tc.manifest), tc.opts...)
+			if tc.wantErr != nil {
+				var invalid *InvalidManifest
+				if !errors.As(err, &invalid) {
+					t.Fatalf("ParseManifests(...): error = %v, want *InvalidManifest", err)
+				}
+
+				got := &InvalidManifest{Source: invalid.Source, Index: invalid.Index, Line: invalid.Line}
+				if !reflect.DeepEqual(got, tc.wantErr) {
+					t.Errorf("ParseManifests(...): error at %+v, want %+v", got, tc.wantErr)
+				}
+
+				if manifests != nil {
+					t.Errorf("ParseManifests(...) = %v, want nil on error", manifests)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseManifests(...): unexpected error: %v", err)
+			}
+
+			var got []manifest
+			for _, m := range manifests {
+				got = append(got, manifest{Name: m.Name, Kind: m.Object.GetKind(), Index: m.Index, Line: m.Line})
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ParseManifests(...) = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNameAnnotationIsRemoved(t *testing.T) {
+	manifests, err := ParseManifests([]byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+  annotations:
+    xfnlib.io/name: config
+`), WithNameAnnotation("xfnlib.io/name"))
+	if err != nil {
+		t.Fatalf("ParseManifests(...): unexpected error: %v", err)
+	}
+
+	if got := manifests[0].Object.GetAnnotations(); got != nil {
+		t.Errorf("ParseManifests(...): annotations = %v, want none", got)
+	}
+}
+
+func TestReadManifestsFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifests/b.yaml":   {Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n")},
+		"manifests/a.yaml":   {Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n")},
+		"manifests/c.json":   {Data: []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "c"}}`)},
+		"manifests/bad.yaml": {Data: []byte("apiVersion: v1\nmetadata:\n  name: bad\n")},
+	}
+
+	manifests, err := ReadManifestsFS(fsys, "manifests/[abc].*")
+	if err != nil {
+		t.Fatalf("ReadManifestsFS(...): unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, m := range manifests {
+		got = append(got, m.Source+"/"+m.Name)
+	}
+	want := []string{"manifests/a.yaml/a", "manifests/b.yaml/b", "manifests/c.json/c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadManifestsFS(...) = %v, want %v", got, want)
+	}
+
+	_, err = ReadManifestsFS(fsys, "manifests/*.yaml")
+	var invalid *InvalidManifest
+	if !errors.As(err, &invalid) || invalid.Source != "manifests/bad.yaml" {
+		t.Errorf("ReadManifestsFS(...): error = %v, want *InvalidManifest of manifests/bad.yaml", err)
+	}
+}
+
+func TestAddManifests(t *testing.T) {
+	manifests, err := ParseManifests([]byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+`))
+	if err != nil {
+		t.Fatalf("ParseManifests(...): unexpected error: %v", err)
+	}
+
+	c := newTestComposition(t, testRequest(t, "", nil, nil))
+	if err := c.AddManifests(manifests); err != nil {
+		t.Fatalf("AddManifests(...): unexpected error: %v", err)
+	}
+
+	for _, n := range []string{"a", "b"} {
+		if _, ok := c.DesiredComposed[resource.Name(n)]; !ok {
+			t.Errorf("AddManifests(...): %q is not desired", n)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package composite
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// mergeMaps recursively merges src into dst and returns dst
+//
+// Nested maps are merged key by key. Any other value in src replaces the value
+// found in dst. Values taken from src are deep copied, so src must only hold
+// JSON values. Pass values of other Go types through `To` first.
+func mergeMaps(dst, src map[string]any) map[string]any {
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+
+	for k, sv := range src {
+		sm, sok := sv.(map[string]any)
+		dm, dok := dst[k].(map[string]any)
+		if sok && dok {
+			dst[k] = mergeMaps(dm, sm)
+			continue
+		}
+		dst[k] = runtime.DeepCopyJSONValue(sv)
+	}
+	return dst
+}
+
+// MergeStrategy decides how `AddDesired` combines a resource with one of the
+// same name that already exists in the pipeline
+type MergeStrategy int
+
+const (
+	// MergeReplace replaces the existing resource. This is the default
+	MergeReplace MergeStrategy = iota
+
+	// MergePatch applies the resource to the existing one as a JSON merge
+	// patch (RFC 7386). Fields set to null are removed from the existing
+	// resource and lists are replaced
+	MergePatch
+
+	// MergeDeep merges maps recursively. Lists are replaced unless a merge
+	// key is configured for them with `WithListMergeKeys`
+	MergeDeep
+)
+
+// ConflictMode decides what happens when `AddDesired` overrides a value set by
+// an earlier step in the pipeline
+type ConflictMode int
+
+const (
+	// ConflictIgnore silently overrides the earlier value. This is the default
+	ConflictIgnore ConflictMode = iota
+
+	// ConflictReport overrides the earlier value and records a warning result
+	// listing the overridden fields
+	ConflictReport
+
+	// ConflictError leaves the existing resource untouched and returns a
+	// `MergeConflict` error
+	ConflictError
+)
+
+// WithMergeStrategy sets how the resource is combined with an existing
+// resource of the same name
+func WithMergeStrategy(s MergeStrategy) DesiredOption {
+	return func(o *desiredOptions) {
+		o.merge = s
+	}
+}
+
+// WithListMergeKeys merges lists by key when using `MergeDeep`
+//
+// Items with the same key are merged and new items are appended. List items
+// nested in lists are addressed with `[*]`.
+//
+// Example:
+//
+//	composite.WithListMergeKeys(map[string]string{
+//		"spec.forProvider.tags":      "key",
+//		"spec.containers[*].ports": "containerPort",
+//	})
+func WithListMergeKeys(keys map[string]string) DesiredOption {
+	return func(o *desiredOptions) {
+		if o.listKeys == nil {
+			o.listKeys = make(map[string]string, len(keys))
+		}
+		for p, k := range keys {
+			o.listKeys[p] = k
+		}
+	}
+}
+
+// WithConflicts sets how overriding a value set by an earlier step in the
+// pipeline is handled
+func WithConflicts(mode ConflictMode) DesiredOption {
+	return func(o *desiredOptions) {
+		o.conflicts = mode
+	}
+}
+
+// merger combines two unstructured objects and records the fields where the
+// existing value is overridden
+type merger struct {
+	strategy  MergeStrategy
+	listKeys  map[string]string
+	conflicts []string
+}
+
+// merge combines src into dst according to the configured strategy
+//
+// Neither input is modified.
+func (m *merger) merge(dst, src map[string]any) (out map[string]any, err error) {
+	var d, s map[string]any
+	if err = To(dst, &d); err != nil {
+		return
+	}
+	if err = To(src, &s); err != nil {
+		return
+	}
+
+	switch m.strategy {
+	case MergePatch:
+		out = m.mergePatch(nil, d, s)
+	case MergeDeep:
+		out, _ = m.mergeDeep(nil, d, s).(map[string]any)
+	default:
+		// Everything the existing object sets differently or we drop is
+		// overridden
+		var overridden ResourceDiff
+		diffValues(nil, s, d, true, &overridden)
+		m.conflicts = append(overridden.Removed, overridden.Changed...)
+		out = src
+	}
+	slices.Sort(m.conflicts)
+	return
+}
+
+// mergePatch applies src to dst as a JSON merge patch
+func (m *merger) mergePatch(path fieldpath.Segments, dst, src map[string]any) map[string]any {
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+
+	for k, sv := range src {
+		p := append(slices.Clone(path), fieldpath.Field(k))
+		dv, exists := dst[k]
+
+		if sv == nil {
+			if exists {
+				m.conflicts = append(m.conflicts, p.String())
+			}
+			delete(dst, k)
+			continue
+		}
+
+		sm, sok := sv.(map[string]any)
+		dm, dok := dv.(map[string]any)
+		switch {
+		case sok && dok:
+			dst[k] = m.mergePatch(p, dm, sm)
+		case sok:
+			m.conflict(p, exists, dv, sv)
+			dst[k] = m.mergePatch(p, nil, sm)
+		default:
+			m.conflict(p, exists, dv, sv)
+			dst[k] = sv
+		}
+	}
+	return dst
+}
+
+// mergeDeep merges src into dst recursively and returns the result
+func (m *merger) mergeDeep(path fieldpath.Segments, dst, src any) any {
+	switch s := src.(type) {
+	case map[string]any:
+		d, ok := dst.(map[string]any)
+		if !ok {
+			m.conflict(path, dst != nil, dst, src)
+			return s
+		}
+
+		for k, sv := range s {
+			p := append(slices.Clone(path), fieldpath.Field(k))
+			if dv, exists := d[k]; exists {
+				d[k] = m.mergeDeep(p, dv, sv)
+				continue
+			}
+			d[k] = sv
+		}
+		return d
+	case []any:
+		d, ok := dst.([]any)
+		key, keyed := m.listKeys[wildcardPath(path)]
+		if !ok || !keyed {
+			m.conflict(path, dst != nil, dst, src)
+			return s
+		}
+
+		for _, item := range s {
+			im, ok := item.(map[string]any)
+			if !ok {
+				d = append(d, item)
+				continue
+			}
+
+			index := slices.IndexFunc(d, func(e any) bool {
+				em, ok := e.(map[string]any)
+				return ok && im[key] != nil && reflect.DeepEqual(em[key], im[key])
+			})
+			if index < 0 {
+				d = append(d, item)
+				continue
+			}
+
+			p := append(slices.Clone(path), fieldpath.Segment{Type: fieldpath.SegmentIndex, Index: uint(index)})
+			d[index] = m.mergeDeep(p, d[index], item)
+		}
+		return d
+	}
+
+	m.conflict(path, dst != nil, dst, src)
+	return src
+}
+
+// conflict records path if an existing value is overridden by a different one
+func (m *merger) conflict(path fieldpath.Segments, exists bool, dst, src any) {
+	if exists && !reflect.DeepEqual(dst, src) {
+		m.conflicts = append(m.conflicts, path.String())
+	}
+}
+
+// wildcardPath renders path with all list indexes replaced by `*`
+func wildcardPath(path fieldpath.Segments) string {
+	p := make(fieldpath.Segments, len(path))
+	for i, s := range path {
+		if s.Type == fieldpath.SegmentIndex {
+			s = fieldpath.Field("*")
+		}
+		p[i] = s
+	}
+	return p.String()
+}
@@ -0,0 +1,192 @@
+package composite
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestMergeMaps(t *testing.T) {
+	cases := map[string]struct {
+		dst  map[string]any
+		src  map[string]any
+		want map[string]any
+	}{
+		"NilDestination": {
+			src:  map[string]any{"a": "b"},
+			want: map[string]any{"a": "b"},
+		},
+		"NestedMaps": {
+			dst:  map[string]any{"spec": map[string]any{"a": "dst", "b": "dst"}},
+			src:  map[string]any{"spec": map[string]any{"a": "src", "c": "src"}},
+			want: map[string]any{"spec": map[string]any{"a": "src", "b": "dst", "c": "src"}},
+		},
+		"ReplacesLists": {
+			dst:  map[string]any{"l": []any{"a", "b"}},
+			src:  map[string]any{"l": []any{"c"}},
+			want: map[string]any{"l": []any{"c"}},
+		},
+		"ReplacesMapWithValue": {
+			dst:  map[string]any{"a": map[string]any{"b": "c"}},
+			src:  map[string]any{"a": "d"},
+			want: map[string]any{"a": "d"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := mergeMaps(tc.dst, tc.src); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("mergeMaps(...) = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMergeMapsCopiesSource(t *testing.T) {
+	src := map[string]any{"l": []any{"a"}}
+	got := mergeMaps(nil, src)
+
+	src["l"].([]any)[0] = "changed"
+	if got["l"].([]any)[0] != "a" {
+		t.Errorf("mergeMaps(...): changing src changed the result to %v", got)
+	}
+}
+
+func TestAddDesiredMerge(t *testing.T) {
+	existing := `{
+		"apiVersion": "ec2.aws.upbound.io/v1beta1",
+		"kind": "VPC",
+		"metadata": {"name": "vpc", "labels": {"a": "upstream"}},
+		"spec": {"forProvider": {
+			"region": "eu-west-1",
+			"tags": [{"key": "owner", "value": "upstream"}, {"key": "team", "value": "upstream"}]
+		}}
+	}`
+
+	cases := map[string]struct {
+		object        string
+		opts          []DesiredOption
+		want          string
+		wantConflicts []string
+		wantWarnings  int
+	}{
+		"Replace": {
+			object: `{
+				"apiVersion": "ec2.aws.upbound.io/v1beta1",
+				"kind": "VPC",
+				"metadata": {"name": "vpc"},
+				"spec": {"forProvider": {"region": "eu-central-1"}}
+			}`,
+			want: `{
+				"apiVersion": "ec2.aws.upbound.io/v1beta1",
+				"kind": "VPC",
+				"metadata": {"name": "vpc"},
+				"spec": {"forProvider": {"region": "eu-central-1"}}
+			}`,
+		},
+		"PatchRemovesNull": {
+			object: `{"metadata": {"labels": {"a": null, "b": "patch"}}}`,
+			opts:   []DesiredOption{WithMergeStrategy(MergePatch)},
+			want: `{
+				"apiVersion": "ec2.aws.upbound.io/v1beta1",
+				"kind": "VPC",
+				"metadata": {"name": "vpc", "labels": {"b": "patch"}},
+				"spec": {"forProvider": {
+					"region": "eu-west-1",
+					"tags": [{"key": "owner", "value": "upstream"}, {"key": "team", "value": "upstream"}]
+				}}
+			}`,
+		},
+		"DeepMergesListsByKey": {
+			object: `{"spec": {"forProvider": {"tags": [
+				{"key": "team", "value": "ours"},
+				{"key": "env", "value": "prod"}
+			]}}}`,
+			opts: []DesiredOption{
+				WithMergeStrategy(MergeDeep),
+				WithListMergeKeys(map[string]string{"spec.forProvider.tags": "key"}),
+			},
+			want: `{
+				"apiVersion": "ec2.aws.upbound.io/v1beta1",
+				"kind": "VPC",
+				"metadata": {"name": "vpc", "labels": {"a": "upstream"}},
+				"spec": {"forProvider": {
+					"region": "eu-west-1",
+					"tags": [
+						{"key": "owner", "value": "upstream"},
+						{"key": "team", "value": "ours"},
+						{"key": "env", "value": "prod"}
+					]
+				}}
+			}`,
+		},
+		"DeepReplacesListsWithoutKey": {
+			object: `{"spec": {"forProvider": {"tags": [{"key": "env", "value": "prod"}]}}}`,
+			opts:   []DesiredOption{WithMergeStrategy(MergeDeep)},
+			want: `{
+				"apiVersion": "ec2.aws.upbound.io/v1beta1",
+				"kind": "VPC",
+				"metadata": {"name": "vpc", "labels": {"a": "upstream"}},
+				"spec": {"forProvider": {
+					"region": "eu-west-1",
+					"tags": [{"key": "env", "value": "prod"}]
+				}}
+			}`,
+		},
+		"ConflictReport": {
+			object: `{"spec": {"forProvider": {"region": "eu-central-1"}}}`,
+			opts:   []DesiredOption{WithMergeStrategy(MergeDeep), WithConflicts(ConflictReport)},
+			want: `{
+				"apiVersion": "ec2.aws.upbound.io/v1beta1",
+				"kind": "VPC",
+				"metadata": {"name": "vpc", "labels": {"a": "upstream"}},
+				"spec": {"forProvider": {
+					"region": "eu-central-1",
+					"tags": [{"key": "owner", "value": "upstream"}, {"key": "team", "value": "upstream"}]
+				}}
+			}`,
+			wantWarnings: 1,
+		},
+		"ConflictError": {
+			object: `{"metadata": {"labels": {"a": "ours"}}, "spec": {"forProvider": {"region": "eu-central-1"}}}`,
+			opts:   []DesiredOption{WithMergeStrategy(MergePatch), WithConflicts(ConflictError)},
+			want:   existing,
+			wantConflicts: []string{
+				"metadata.labels.a",
+				"spec.forProvider.region",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestComposition(t, testRequest(t, "", nil, map[string]string{"vpc": existing}))
+
+			err := c.AddDesired("vpc", &unstructured.Unstructured{Object: testMap(t, tc.object)}, tc.opts...)
+
+			var mc *MergeConflict
+			switch {
+			case tc.wantConflicts != nil:
+				if !errors.As(err, &mc) {
+					t.Fatalf("AddDesired(...): error = %v, want *MergeConflict", err)
+				}
+				if !reflect.DeepEqual(mc.Paths, tc.wantConflicts) {
+					t.Errorf("AddDesired(...): conflicts = %v, want %v", mc.Paths, tc.wantConflicts)
+				}
+			case err != nil:
+				t.Fatalf("AddDesired(...): unexpected error: %v", err)
+			}
+
+			if got := len(c.results); got != tc.wantWarnings {
+				t.Errorf("AddDesired(...): %d results, want %d", got, tc.wantWarnings)
+			}
+
+			want := testMap(t, tc.want)
+			if got := c.DesiredComposed["vpc"].Resource.Object; !reflect.DeepEqual(got, want) {
+				t.Errorf("AddDesired(...): vpc = %v, want %v", got, want)
+			}
+		})
+	}
+}
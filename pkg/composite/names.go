@@ -0,0 +1,160 @@
+package composite
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// NameRule is the set of kubernetes naming rules a name must satisfy
+type NameRule int
+
+const (
+	// DNS1123Label names are at most 63 characters of lower case
+	// alphanumerics and `-`. Most resources that end up in labels or DNS
+	// records need this
+	DNS1123Label NameRule = iota
+
+	// DNS1123Subdomain names are at most 253 characters of lower case
+	// alphanumerics, `-` and `.`
+	DNS1123Subdomain
+)
+
+// nameHashLength is the number of hash characters appended to sanitised or
+// truncated names
+const nameHashLength = 8
+
+// ResourceName builds a stable DNS-1123 label from the given parts
+//
+// Parts are joined with `-`. Empty parts are skipped. When a part has to be
+// lower cased or has characters that are not valid in a label replaced, the
+// name is suffixed with a hash of the parts as given so that different inputs
+// don't collide. Names longer than 63 characters are truncated and suffixed
+// with the hash too. Names made of valid parts, such as a dashed composite
+// name, are returned as they are.
+//
+// Example:
+//
+//	name := composite.ResourceName(xrName, "subnet", "eu-west-1a")
+func ResourceName(parts ...string) string {
+	return buildName(DNS1123Label, parts...)
+}
+
+// SubdomainName builds a stable DNS-1123 subdomain from the given parts
+//
+// This works like `ResourceName` but keeps `.` and allows up to 253
+// characters.
+func SubdomainName(parts ...string) string {
+	return buildName(DNS1123Subdomain, parts...)
+}
+
+// PipelineName builds the pipeline name passed to `AddDesired` from the given
+// parts using the same rules as `ResourceName`
+func PipelineName(parts ...string) string {
+	return buildName(DNS1123Label, parts...)
+}
+
+// ValidateName checks name against the given naming rule
+func ValidateName(name string, rule NameRule) (err error) {
+	var reasons []string
+	switch rule {
+	case DNS1123Subdomain:
+		reasons = validation.IsDNS1123Subdomain(name)
+	default:
+		reasons = validation.IsDNS1123Label(name)
+	}
+
+	if len(reasons) > 0 {
+		err = &InvalidName{Name: name, Reasons: reasons}
+	}
+	return
+}
+
+// ResourceName builds a stable DNS-1123 label for a composed resource from the
+// name of the observed composite resource and the given parts
+func (c *TypedComposition[XR, In]) ResourceName(parts ...string) string {
+	var base string
+	if c.observed != nil {
+		base = c.observed.Resource.GetName()
+	}
+	return ResourceName(append([]string{base}, parts...)...)
+}
+
+// buildName sanitises and joins parts, truncating the result to the maximum
+// length allowed by rule
+//
+// A hash of the original parts is appended when a part was sanitised or the
+// result is truncated.
+func buildName(rule NameRule, parts ...string) string {
+	maxLength := validation.DNS1123LabelMaxLength
+	if rule == DNS1123Subdomain {
+		maxLength = validation.DNS1123SubdomainMaxLength
+	}
+
+	var (
+		given, sanitised []string
+		changed          bool
+	)
+	for _, p := range parts {
+		if p == "" {
+			continue
+		}
+		given = append(given, p)
+
+		s := sanitiseName(rule, p)
+		if s != p {
+			changed = true
+		}
+
+		if s != "" {
+			sanitised = append(sanitised, s)
+		}
+	}
+
+	name := strings.Join(sanitised, "-")
+	if !changed && len(name) <= maxLength {
+		return name
+	}
+
+	// Join with NUL so that the hash tells apart where the parts were split
+	sum := sha256.Sum256([]byte(strings.Join(given, "\x00")))
+	hash := hex.EncodeToString(sum[:])[:nameHashLength]
+	if len(name) > maxLength-nameHashLength-1 {
+		name = strings.TrimRight(name[:maxLength-nameHashLength-1], "-.")
+	}
+
+	if name == "" {
+		return hash
+	}
+	return name + "-" + hash
+}
+
+// sanitiseName lower cases s and replaces characters not allowed by rule
+func sanitiseName(rule NameRule, s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '.' && rule == DNS1123Subdomain:
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+
+	// Collapse separators and make sure the name starts and ends with an
+	// alphanumeric character
+	name := b.String()
+	for strings.Contains(name, "--") {
+		name = strings.ReplaceAll(name, "--", "-")
+	}
+	for strings.Contains(name, "..") {
+		name = strings.ReplaceAll(name, "..", ".")
+	}
+	name = strings.ReplaceAll(name, "-.", ".")
+	name = strings.ReplaceAll(name, ".-", ".")
+	return strings.Trim(name, "-.")
+}
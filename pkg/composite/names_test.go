@@ -0,0 +1,111 @@
+package composite
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// nameHash returns the hash suffix buildName appends for parts
+func nameHash(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])[:nameHashLength]
+}
+
+func TestResourceName(t *testing.T) {
+	cases := map[string]struct {
+		parts []string
+		want  string
+	}{
+		"Plain": {
+			parts: []string{"xr", "subnet", "a"},
+			want:  "xr-subnet-a",
+		},
+		"SkipsEmptyParts": {
+			parts: []string{"xr", "", "a"},
+			want:  "xr-a",
+		},
+		"SinglePartWithSeparator": {
+			parts: []string{"eu-west-1a"},
+			want:  "eu-west-1a",
+		},
+		"DashedCompositeName": {
+			parts: []string{"my-cluster-x7k2p", "subnet", "eu-west-1a"},
+			want:  "my-cluster-x7k2p-subnet-eu-west-1a",
+		},
+		"DashedParts": {
+			parts: []string{"my-cluster", "node-pool", "spot-a"},
+			want:  "my-cluster-node-pool-spot-a",
+		},
+		"Sanitised": {
+			parts: []string{"my-cluster", "Subnet_A"},
+			want:  "my-cluster-subnet-a-" + nameHash("my-cluster", "Subnet_A"),
+		},
+		"Truncated": {
+			parts: []string{"my-cluster", strings.Repeat("a", 60)},
+			want:  "my-cluster-" + strings.Repeat("a", 43) + "-" + nameHash("my-cluster", strings.Repeat("a", 60)),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := ResourceName(tc.parts...); got != tc.want {
+				t.Errorf("ResourceName(%q) = %q, want %q", tc.parts, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResourceNameDoesNotCollide(t *testing.T) {
+	cases := map[string]struct {
+		a, b []string
+	}{
+		"Sanitised": {
+			a: []string{"xr", "Subnet_A"},
+			b: []string{"xr", "subnet-a"},
+		},
+		"Truncated": {
+			a: []string{"xr", strings.Repeat("a", 70), "1"},
+			b: []string{"xr", strings.Repeat("a", 70), "2"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, b := ResourceName(tc.a...), ResourceName(tc.b...)
+			if a == b {
+				t.Errorf("ResourceName(%q) and ResourceName(%q) both return %q", tc.a, tc.b, a)
+			}
+
+			for _, n := range []string{a, b} {
+				if err := ValidateName(n, DNS1123Label); err != nil {
+					t.Errorf("ValidateName(%q): %v", n, err)
+				}
+			}
+
+			if a != ResourceName(tc.a...) {
+				t.Errorf("ResourceName(%q) is not stable", tc.a)
+			}
+		})
+	}
+}
+
+func TestSubdomainName(t *testing.T) {
+	n := SubdomainName("xr", strings.Repeat("a.b", 100))
+	if len(n) > 253 {
+		t.Errorf("SubdomainName(...) is %d characters long", len(n))
+	}
+
+	if err := ValidateName(n, DNS1123Subdomain); err != nil {
+		t.Errorf("ValidateName(%q): %v", n, err)
+	}
+}
+
+func TestCompositionResourceName(t *testing.T) {
+	c := newTestComposition(t, testRequest(t, `{"apiVersion": "v1", "kind": "X", "metadata": {"name": "my-cluster-x7k2p"}}`, nil, nil))
+
+	if got, want := c.ResourceName("subnet", "eu-west-1a"), "my-cluster-x7k2p-subnet-eu-west-1a"; got != want {
+		t.Errorf("ResourceName(...) = %q, want %q", got, want)
+	}
+}
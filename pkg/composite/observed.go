@@ -0,0 +1,196 @@
+package composite
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/function-sdk-go/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ObservedState describes whether a composed resource exists in the cluster
+type ObservedState int
+
+const (
+	// ObservedMissing resources are neither observed nor desired by any step
+	// in the pipeline
+	ObservedMissing ObservedState = iota
+
+	// ObservedPending resources are desired but have not been created yet
+	ObservedPending
+
+	// ObservedExists resources exist in the cluster
+	ObservedExists
+)
+
+// ObservedState returns whether the composed resource `n` exists, is waiting
+// to be created or is not known to the pipeline at all
+//
+// For provider-kubernetes Objects the resource is pending until the provider
+// has observed the wrapped manifest.
+func (c *TypedComposition[XR, In]) ObservedState(n string) ObservedState {
+	if _, ok := c.observedObject(n); ok {
+		return ObservedExists
+	}
+
+	if _, ok := c.ObservedComposed[resource.Name(n)]; ok {
+		return ObservedPending
+	}
+
+	if _, ok := c.DesiredComposed[resource.Name(n)]; ok {
+		return ObservedPending
+	}
+	return ObservedMissing
+}
+
+// GetObserved decodes the observed composed resource `n` into `into`
+//
+// For provider-kubernetes Objects the wrapped manifest as observed by the
+// provider is decoded instead of the Object itself.
+//
+// `found` is false when the resource does not exist yet. If the resource is
+// not desired by any step in the pipeline either, a `MissingResource` error is
+// returned.
+func (c *TypedComposition[XR, In]) GetObserved(n string, into any) (found bool, err error) {
+	var object map[string]any
+	if object, err = c.observedFor(n); err != nil {
+		var waiting *WaitingForResource
+		if errors.As(err, &waiting) {
+			err = nil
+		}
+		return
+	}
+
+	if err = To(object, into); err != nil {
+		err = errors.Wrapf(err, "cannot convert observed resource %q to %T", n, into)
+		return
+	}
+	found = true
+	return
+}
+
+// ObservedValue returns the value at `path` on the observed composed resource
+// `n`
+//
+// Paths on provider-kubernetes Objects are evaluated against the wrapped
+// manifest. A `WaitingForResource` error is returned while the resource does
+// not exist and a `MissingResource` error if no step in the pipeline desires
+// it.
+//
+// Example:
+//
+//	arn, err := composed.ObservedString("role", "status.atProvider.arn")
+func (c *TypedComposition[XR, In]) ObservedValue(n, path string) (v any, err error) {
+	var object map[string]any
+	if object, err = c.observedFor(n); err != nil {
+		return
+	}
+
+	if v, err = fieldpath.Pave(object).GetValue(path); err != nil {
+		err = errors.Wrapf(err, "cannot get %q from observed resource %q", path, n)
+	}
+	return
+}
+
+// ObservedString returns the string at `path` on the observed composed
+// resource `n`
+func (c *TypedComposition[XR, In]) ObservedString(n, path string) (v string, err error) {
+	var object map[string]any
+	if object, err = c.observedFor(n); err != nil {
+		return
+	}
+
+	if v, err = fieldpath.Pave(object).GetString(path); err != nil {
+		err = errors.Wrapf(err, "cannot get %q from observed resource %q", path, n)
+	}
+	return
+}
+
+// ObservedInteger returns the integer at `path` on the observed composed
+// resource `n`
+//
+// Numbers in observed resources are decoded as float64, so any whole number is
+// accepted.
+func (c *TypedComposition[XR, In]) ObservedInteger(n, path string) (v int64, err error) {
+	var object map[string]any
+	if object, err = c.observedFor(n); err != nil {
+		return
+	}
+
+	if err = fieldpath.Pave(object).GetValueInto(path, &v); err != nil {
+		err = errors.Wrapf(err, "cannot get %q from observed resource %q", path, n)
+	}
+	return
+}
+
+// ObservedBool returns the boolean at `path` on the observed composed resource
+// `n`
+func (c *TypedComposition[XR, In]) ObservedBool(n, path string) (v bool, err error) {
+	var object map[string]any
+	if object, err = c.observedFor(n); err != nil {
+		return
+	}
+
+	if v, err = fieldpath.Pave(object).GetBool(path); err != nil {
+		err = errors.Wrapf(err, "cannot get %q from observed resource %q", path, n)
+	}
+	return
+}
+
+// observedFor returns the observed object for `n` or an error describing why
+// it is not available
+func (c *TypedComposition[XR, In]) observedFor(n string) (object map[string]any, err error) {
+	var ok bool
+	if object, ok = c.observedObject(n); ok {
+		return
+	}
+
+	if c.ObservedState(n) == ObservedMissing {
+		err = &MissingResource{Name: n}
+		return
+	}
+
+	// The resource may be observed but no longer desired, for example a
+	// provider-kubernetes Object whose manifest has not been observed yet
+	var source map[string]any
+	if d, ok := c.DesiredComposed[resource.Name(n)]; ok {
+		source = d.Resource.Object
+	} else if o, ok := c.ObservedComposed[resource.Name(n)]; ok {
+		source = o.Resource.Object
+	}
+	err = &WaitingForResource{ErrorContext: objectContext(source, ""), Name: n}
+	return
+}
+
+// observedObject returns the observed object for `n`, unwrapping the manifest
+// of provider-kubernetes Objects
+func (c *TypedComposition[XR, In]) observedObject(n string) (object map[string]any, ok bool) {
+	var observed resource.ObservedComposed
+	if observed, ok = c.ObservedComposed[resource.Name(n)]; !ok {
+		return
+	}
+
+	object = observed.Resource.Object
+	if !isKubernetesObject(&observed.Resource.Unstructured) {
+		return
+	}
+
+	object, ok, _ = unstructured.NestedMap(object, "status", "atProvider", "manifest")
+	return
+}
+
+// ObservedCompositeObject returns a copy of the raw observed composite
+// resource
+//
+// Use this where the untyped object is needed, for example as template data.
+func (c *TypedComposition[XR, In]) ObservedCompositeObject() map[string]any {
+	return runtime.DeepCopyJSON(c.observedCompositeObject())
+}
+
+// observedCompositeObject returns the raw observed composite resource
+func (c *TypedComposition[XR, In]) observedCompositeObject() map[string]any {
+	if c.observed == nil {
+		return nil
+	}
+	return c.observed.Resource.Object
+}
@@ -0,0 +1,207 @@
+package composite
+
+import (
+	"errors"
+	"testing"
+)
+
+const (
+	testObservedBucket = `{
+		"apiVersion": "s3.aws.upbound.io/v1beta1",
+		"kind": "Bucket",
+		"metadata": {"name": "bucket"},
+		"status": {"atProvider": {"arn": "arn:aws:s3:::bucket", "versioning": true, "objects": 2}}
+	}`
+
+	testObservedObject = `{
+		"apiVersion": "kubernetes.crossplane.io/v1alpha2",
+		"kind": "Object",
+		"metadata": {"name": "obj"},
+		"status": {"atProvider": {"manifest": {
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {"name": "cm"},
+			"data": {"key": "value"}
+		}}}
+	}`
+
+	// testObservedPendingObject is an Object the provider has not observed the
+	// manifest of yet
+	testObservedPendingObject = `{
+		"apiVersion": "kubernetes.crossplane.io/v1alpha2",
+		"kind": "Object",
+		"metadata": {"name": "obj"}
+	}`
+
+	testDesiredBucket = `{"apiVersion": "s3.aws.upbound.io/v1beta1", "kind": "Bucket"}`
+)
+
+func TestObservedState(t *testing.T) {
+	cases := map[string]struct {
+		observed map[string]string
+		desired  map[string]string
+		want     ObservedState
+	}{
+		"Exists": {
+			observed: map[string]string{"r": testObservedBucket},
+			want:     ObservedExists,
+		},
+		"ObjectExists": {
+			observed: map[string]string{"r": testObservedObject},
+			want:     ObservedExists,
+		},
+		"ObjectWithoutManifest": {
+			observed: map[string]string{"r": testObservedPendingObject},
+			want:     ObservedPending,
+		},
+		"Desired": {
+			desired: map[string]string{"r": testDesiredBucket},
+			want:    ObservedPending,
+		},
+		"Missing": {
+			want: ObservedMissing,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestComposition(t, testRequest(t, "", tc.observed, tc.desired))
+			if got := c.ObservedState("r"); got != tc.want {
+				t.Errorf("ObservedState(...) = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestObservedString(t *testing.T) {
+	cases := map[string]struct {
+		observed map[string]string
+		desired  map[string]string
+		path     string
+		want     string
+		wantErr  any
+	}{
+		"Resource": {
+			observed: map[string]string{"r": testObservedBucket},
+			path:     "status.atProvider.arn",
+			want:     "arn:aws:s3:::bucket",
+		},
+		"ObjectManifest": {
+			observed: map[string]string{"r": testObservedObject},
+			path:     "data.key",
+			want:     "value",
+		},
+		"WaitingForDesired": {
+			desired: map[string]string{"r": testDesiredBucket},
+			path:    "status.atProvider.arn",
+			wantErr: &WaitingForResource{},
+		},
+		"WaitingForObjectNotDesired": {
+			observed: map[string]string{"r": testObservedPendingObject},
+			path:     "status.x",
+			wantErr:  &WaitingForResource{},
+		},
+		"Missing": {
+			path:    "status.atProvider.arn",
+			wantErr: &MissingResource{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestComposition(t, testRequest(t, "", tc.observed, tc.desired))
+
+			got, err := c.ObservedString("r", tc.path)
+			switch want := tc.wantErr.(type) {
+			case *WaitingForResource:
+				if !errors.As(err, &want) {
+					t.Fatalf("ObservedString(...): error = %v, want *WaitingForResource", err)
+				}
+			case *MissingResource:
+				if !errors.As(err, &want) {
+					t.Fatalf("ObservedString(...): error = %v, want *MissingResource", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("ObservedString(...): unexpected error: %v", err)
+				}
+			}
+
+			if got != tc.want {
+				t.Errorf("ObservedString(...) = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestObservedWaitingForObjectContext(t *testing.T) {
+	c := newTestComposition(t, testRequest(t, "", map[string]string{"obj": testObservedPendingObject}, nil))
+
+	_, err := c.ObservedString("obj", "status.x")
+
+	var waiting *WaitingForResource
+	if !errors.As(err, &waiting) {
+		t.Fatalf("ObservedString(...): error = %v, want *WaitingForResource", err)
+	}
+	if waiting.GVK.Kind != "Object" || waiting.ObjectName != "obj" {
+		t.Errorf("ObservedString(...): error context = %+v, want the observed Object", waiting.ErrorContext)
+	}
+}
+
+func TestObservedTypedGetters(t *testing.T) {
+	c := newTestComposition(t, testRequest(t, "", map[string]string{"r": testObservedBucket}, nil))
+
+	if got, err := c.ObservedBool("r", "status.atProvider.versioning"); err != nil || !got {
+		t.Errorf("ObservedBool(...) = %v, %v, want true, nil", got, err)
+	}
+	if got, err := c.ObservedInteger("r", "status.atProvider.objects"); err != nil || got != 2 {
+		t.Errorf("ObservedInteger(...) = %v, %v, want 2, nil", got, err)
+	}
+	if _, err := c.ObservedValue("r", "status.atProvider.missing"); err == nil {
+		t.Error("ObservedValue(...): want error for a missing field")
+	}
+}
+
+func TestGetObserved(t *testing.T) {
+	cases := map[string]struct {
+		observed  map[string]string
+		desired   map[string]string
+		wantFound bool
+		wantName  string
+		wantErr   bool
+	}{
+		"ObjectManifest": {
+			observed:  map[string]string{"r": testObservedObject},
+			wantFound: true,
+			wantName:  "cm",
+		},
+		"ObjectWithoutManifest": {
+			observed: map[string]string{"r": testObservedPendingObject},
+		},
+		"Pending": {
+			desired: map[string]string{"r": testDesiredBucket},
+		},
+		"Missing": {
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestComposition(t, testRequest(t, "", tc.observed, tc.desired))
+
+			var into struct {
+				Metadata struct {
+					Name string `json:"name"`
+				} `json:"metadata"`
+			}
+			found, err := c.GetObserved("r", &into)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("GetObserved(...): error = %v, want error %v", err, tc.wantErr)
+			}
+			if found != tc.wantFound || into.Metadata.Name != tc.wantName {
+				t.Errorf("GetObserved(...) = %v, %q, want %v, %q", found, into.Metadata.Name, tc.wantFound, tc.wantName)
+			}
+		})
+	}
+}
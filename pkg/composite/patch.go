@@ -0,0 +1,337 @@
+package composite
+
+import (
+	"fmt"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/function-sdk-go/resource"
+
+	"github.com/giantswarm/xfnlib/pkg/composite/transform"
+)
+
+// PatchType is the type of a patch
+type PatchType string
+
+// Supported patch types
+const (
+	// PatchTypeFromCompositeFieldPath copies a field from the observed
+	// composite resource to the desired composed resource
+	PatchTypeFromCompositeFieldPath PatchType = "FromCompositeFieldPath"
+
+	// PatchTypeToCompositeFieldPath copies a field from the observed composed
+	// resource to the status of the desired composite resource
+	PatchTypeToCompositeFieldPath PatchType = "ToCompositeFieldPath"
+
+	// PatchTypeCombineFromComposite combines several fields of the observed
+	// composite resource into a field of the desired composed resource
+	PatchTypeCombineFromComposite PatchType = "CombineFromComposite"
+
+	// PatchTypeFromEnvironmentFieldPath copies a field from the environment
+	// in the pipeline context to the desired composed resource
+	PatchTypeFromEnvironmentFieldPath PatchType = "FromEnvironmentFieldPath"
+)
+
+// FromFieldPathPolicy decides what happens when the source field of a patch
+// does not exist
+type FromFieldPathPolicy string
+
+const (
+	// FromFieldPathPolicyOptional skips the patch. This is the default
+	FromFieldPathPolicyOptional FromFieldPathPolicy = "Optional"
+
+	// FromFieldPathPolicyRequired fails the patch
+	FromFieldPathPolicyRequired FromFieldPathPolicy = "Required"
+)
+
+// CombineStrategy is the strategy used to combine variables
+type CombineStrategy string
+
+// CombineStrategyString formats the variables with a format string
+const CombineStrategyString CombineStrategy = "string"
+
+// Patch copies a value from a source field to a destination field
+//
+// Patches carry json tags so they can be embedded in the function Input.
+//
+// +kubebuilder:object:generate=true
+type Patch struct {
+	// Type of the patch. Defaults to FromCompositeFieldPath
+	Type PatchType `json:"type,omitempty"`
+
+	// FromFieldPath is the source field of the patch
+	FromFieldPath *string `json:"fromFieldPath,omitempty"`
+
+	// Combine the values of several source fields. Required for
+	// CombineFromComposite patches
+	Combine *Combine `json:"combine,omitempty"`
+
+	// ToFieldPath is the destination field of the patch. Defaults to
+	// FromFieldPath
+	ToFieldPath *string `json:"toFieldPath,omitempty"`
+
+	// Transforms are applied in order to the source value before it is
+	// written to the destination field
+	Transforms []transform.Transform `json:"transforms,omitempty"`
+
+	// Policy configures the behaviour of the patch
+	Policy *PatchPolicy `json:"policy,omitempty"`
+}
+
+// PatchPolicy configures the behaviour of a patch
+//
+// +kubebuilder:object:generate=true
+type PatchPolicy struct {
+	// FromFieldPath decides what happens when the source field does not
+	// exist. Defaults to Optional
+	FromFieldPath *FromFieldPathPolicy `json:"fromFieldPath,omitempty"`
+
+	// MergeOptions merges the value into the destination field instead of
+	// replacing it
+	MergeOptions *xpv1.MergeOptions `json:"mergeOptions,omitempty"`
+}
+
+// Combine combines several source fields into a single value
+//
+// +kubebuilder:object:generate=true
+type Combine struct {
+	// Variables are the source fields to combine
+	Variables []CombineVariable `json:"variables"`
+
+	// Strategy used to combine the variables. Defaults to string
+	Strategy CombineStrategy `json:"strategy,omitempty"`
+
+	// String configures the string strategy
+	String *StringCombine `json:"string,omitempty"`
+}
+
+// CombineVariable is a source field of a Combine
+//
+// +kubebuilder:object:generate=true
+type CombineVariable struct {
+	// FromFieldPath is the source field
+	FromFieldPath string `json:"fromFieldPath"`
+}
+
+// StringCombine combines variables with a format string
+//
+// +kubebuilder:object:generate=true
+type StringCombine struct {
+	// Format is a `fmt` format string receiving the variables in order
+	Format string `json:"format"`
+}
+
+// ResourcePatches binds a set of patches to a composed resource
+//
+// Embed a list of these in the function Input to drive patching from the
+// composition.
+//
+// +kubebuilder:object:generate=true
+type ResourcePatches struct {
+	// Name is the pipeline name of the composed resource
+	Name string `json:"name"`
+
+	// Patches to apply to the composed resource
+	Patches []Patch `json:"patches"`
+}
+
+// ApplyPatches applies patches to the composed resource `n`
+//
+// The resource must have been added with `AddDesired` before patching. Patches
+// are applied in order and the first failing patch stops patching.
+//
+// Example:
+//
+//	err := composed.ApplyPatches("vpc", composite.Patch{
+//		FromFieldPath: ptr.To("spec.region"),
+//		ToFieldPath:   ptr.To("spec.forProvider.region"),
+//	})
+func (c *TypedComposition[XR, In]) ApplyPatches(n string, patches ...Patch) (err error) {
+	for i, p := range patches {
+		if err = c.applyPatch(n, p); err != nil {
+			err = errors.Wrapf(err, "cannot apply patch %d of %q", i, n)
+			return
+		}
+	}
+	return
+}
+
+// ApplyResourcePatches applies each set of patches to its composed resource
+func (c *TypedComposition[XR, In]) ApplyResourcePatches(sets []ResourcePatches) (err error) {
+	for _, s := range sets {
+		if err = c.ApplyPatches(s.Name, s.Patches...); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// applyPatch applies a single patch to the composed resource n
+func (c *TypedComposition[XR, In]) applyPatch(n string, p Patch) (err error) {
+	var (
+		value any
+		found bool
+	)
+
+	switch p.Type {
+	case PatchTypeFromCompositeFieldPath, "":
+		value, found, err = patchSource(c.observedCompositeObject(), p.FromFieldPath)
+	case PatchTypeFromEnvironmentFieldPath:
+		var env map[string]any
+		if _, err = c.Environment(&env); err != nil {
+			return
+		}
+		value, found, err = patchSource(env, p.FromFieldPath)
+	case PatchTypeToCompositeFieldPath:
+		var observed map[string]any
+		if o, ok := c.ObservedComposed[resource.Name(n)]; ok {
+			observed = o.Resource.Object
+		}
+		value, found, err = patchSource(observed, p.FromFieldPath)
+	case PatchTypeCombineFromComposite:
+		value, found, err = c.combine(p.Combine)
+	default:
+		err = errors.Errorf("unsupported patch type %q", p.Type)
+	}
+
+	if err != nil {
+		return
+	}
+
+	if !found {
+		if p.required() {
+			err = errors.Errorf("required field %q not found", p.from())
+		}
+		return
+	}
+
+	if value, err = transform.Apply(value, p.Transforms...); err != nil {
+		return
+	}
+
+	to := p.to()
+	if to == "" {
+		err = errors.New("patch has no destination field path")
+		return
+	}
+
+	if p.Type == PatchTypeToCompositeFieldPath {
+		if err = validateStatusPath(to); err != nil {
+			return
+		}
+		return patchDestination(c.DesiredComposite.Resource.Object, to, value, p.mergeOptions())
+	}
+
+	d, ok := c.DesiredComposed[resource.Name(n)]
+	if !ok {
+		err = &MissingResource{Name: n}
+		return
+	}
+	return patchDestination(d.Resource.Object, to, value, p.mergeOptions())
+}
+
+// patchSource reads the value at path from object
+func patchSource(object map[string]any, path *string) (value any, found bool, err error) {
+	if path == nil || *path == "" {
+		err = errors.New("patch has no source field path")
+		return
+	}
+
+	if object == nil {
+		return
+	}
+
+	if value, err = fieldpath.Pave(object).GetValue(*path); err != nil {
+		if fieldpath.IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+	found = true
+	return
+}
+
+// patchDestination writes value to path on object
+func patchDestination(object map[string]any, path string, value any, mo *xpv1.MergeOptions) (err error) {
+	var v any
+	if err = To(value, &v); err != nil {
+		return
+	}
+
+	if mo != nil {
+		err = fieldpath.Pave(object).MergeValue(path, v, mo)
+	} else {
+		err = fieldpath.Pave(object).SetValue(path, v)
+	}
+	return errors.Wrapf(err, "cannot set %q", path)
+}
+
+// combine reads the variables of cmb from the observed composite resource and
+// combines them into a single value
+func (c *TypedComposition[XR, In]) combine(cmb *Combine) (value any, found bool, err error) {
+	if cmb == nil || len(cmb.Variables) == 0 {
+		err = errors.New("combine patch has no variables")
+		return
+	}
+
+	values := make([]any, len(cmb.Variables))
+	for i, v := range cmb.Variables {
+		if values[i], found, err = patchSource(c.observedCompositeObject(), &v.FromFieldPath); err != nil || !found {
+			return
+		}
+	}
+
+	switch cmb.Strategy {
+	case CombineStrategyString, "":
+		if cmb.String == nil {
+			err = errors.New("string combine has no format")
+			return
+		}
+		value = fmt.Sprintf(cmb.String.Format, values...)
+	default:
+		err = errors.Errorf("unsupported combine strategy %q", cmb.Strategy)
+	}
+	return
+}
+
+// from returns the source field path of the patch for error messages
+func (p Patch) from() string {
+	if p.FromFieldPath != nil {
+		return *p.FromFieldPath
+	}
+
+	if p.Combine != nil {
+		var paths []string
+		for _, v := range p.Combine.Variables {
+			paths = append(paths, v.FromFieldPath)
+		}
+		return fmt.Sprint(paths)
+	}
+	return ""
+}
+
+// to returns the destination field path of the patch
+func (p Patch) to() string {
+	if p.ToFieldPath != nil {
+		return *p.ToFieldPath
+	}
+
+	if p.FromFieldPath != nil {
+		return *p.FromFieldPath
+	}
+	return ""
+}
+
+// required returns true if the source of the patch must exist
+func (p Patch) required() bool {
+	return p.Policy != nil && p.Policy.FromFieldPath != nil && *p.Policy.FromFieldPath == FromFieldPathPolicyRequired
+}
+
+// mergeOptions returns the merge options of the patch
+func (p Patch) mergeOptions() *xpv1.MergeOptions {
+	if p.Policy == nil {
+		return nil
+	}
+	return p.Policy.MergeOptions
+}
@@ -0,0 +1,72 @@
+package composite
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestApplyResourcePatchesFromInput(t *testing.T) {
+	input := `[{
+		"name": "bucket",
+		"patches": [
+			{
+				"fromFieldPath": "spec.region",
+				"toFieldPath": "spec.forProvider.region",
+				"transforms": [{"type": "string", "string": {"type": "Format", "fmt": "%s-a"}}]
+			},
+			{
+				"type": "CombineFromComposite",
+				"combine": {
+					"variables": [{"fromFieldPath": "metadata.name"}, {"fromFieldPath": "spec.region"}],
+					"strategy": "string",
+					"string": {"format": "%s-%s"}
+				},
+				"toFieldPath": "metadata.annotations[example.org/id]"
+			}
+		]
+	}]`
+
+	var sets []ResourcePatches
+	if err := json.Unmarshal([]byte(input), &sets); err != nil {
+		t.Fatalf("cannot unmarshal patches: %v", err)
+	}
+
+	c := newTestComposition(t, testRequest(t, "", nil, map[string]string{
+		"bucket": `{"apiVersion": "s3.aws.upbound.io/v1beta1", "kind": "Bucket"}`,
+	}))
+
+	if err := c.ApplyResourcePatches(sets); err != nil {
+		t.Fatalf("ApplyResourcePatches(...): unexpected error: %v", err)
+	}
+
+	want := map[string]any{
+		"apiVersion": "s3.aws.upbound.io/v1beta1",
+		"kind":       "Bucket",
+		"metadata": map[string]any{
+			"annotations": map[string]any{"example.org/id": "xr-eu-west-1"},
+		},
+		"spec": map[string]any{
+			"forProvider": map[string]any{"region": "eu-west-1-a"},
+		},
+	}
+	if got := c.DesiredComposed["bucket"].Resource.Object; !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyResourcePatches(...): bucket = %v, want %v", got, want)
+	}
+}
+
+func TestResourcePatchesDeepCopy(t *testing.T) {
+	from, to := "spec.region", "spec.forProvider.region"
+	in := &ResourcePatches{
+		Name:    "bucket",
+		Patches: []Patch{{FromFieldPath: &from, ToFieldPath: &to}},
+	}
+	out := in.DeepCopy()
+
+	*out.Patches[0].FromFieldPath = "spec.zone"
+	out.Patches = append(out.Patches, Patch{})
+
+	if len(in.Patches) != 1 || *in.Patches[0].FromFieldPath != "spec.region" {
+		t.Errorf("DeepCopy(): changing the copy changed the original to %+v", in)
+	}
+}
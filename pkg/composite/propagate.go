@@ -0,0 +1,165 @@
+package composite
+
+import (
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Labels Crossplane uses to link composed resources to their composite and
+// claim
+const (
+	LabelComposite      = "crossplane.io/composite"
+	LabelClaimName      = "crossplane.io/claim-name"
+	LabelClaimNamespace = "crossplane.io/claim-namespace"
+)
+
+// Propagation lists the labels and annotations copied from the observed
+// composite resource onto each desired composed resource
+//
+// Keys are matched exactly or, when ending in `*`, by prefix. A single `*`
+// matches every key. Deny lists take precedence over allow lists.
+type Propagation struct {
+	// Labels to copy from the composite resource
+	Labels []string
+
+	// DenyLabels are never copied, even when matched by Labels
+	DenyLabels []string
+
+	// Annotations to copy from the composite resource
+	Annotations []string
+
+	// DenyAnnotations are never copied, even when matched by Annotations
+	DenyAnnotations []string
+}
+
+// SetPropagation enables propagation of labels and annotations from the
+// observed composite resource onto resources added with `AddDesired`
+//
+// Besides the configured keys, the `crossplane.io/composite`,
+// `crossplane.io/claim-name` and `crossplane.io/claim-namespace` labels are
+// always set unless denied. Labels and annotations already set on a resource
+// are never overwritten. For provider-kubernetes Objects, the wrapped manifest
+// receives the same labels and annotations.
+func (c *TypedComposition[XR, In]) SetPropagation(p Propagation) {
+	c.propagation = &p
+}
+
+// WithoutPropagation disables label and annotation propagation for a single
+// resource
+func WithoutPropagation() DesiredOption {
+	return func(o *desiredOptions) {
+		o.noPropagation = true
+	}
+}
+
+// propagate copies u and applies the labels and annotations to be propagated
+func (c *TypedComposition[XR, In]) propagate(u *unstructured.Unstructured) (out *unstructured.Unstructured, err error) {
+	out = &unstructured.Unstructured{}
+	if err = To(u.Object, &out.Object); err != nil {
+		err = errors.Wrapf(err, "cannot copy %T", u)
+		return
+	}
+
+	labels, annotations := c.propagated()
+	setMissing(out, labels, annotations)
+
+	if isKubernetesObject(out) {
+		manifest, ok, _ := unstructured.NestedMap(out.Object, "spec", "forProvider", "manifest")
+		if ok {
+			m := &unstructured.Unstructured{Object: manifest}
+			setMissing(m, labels, annotations)
+			err = unstructured.SetNestedMap(out.Object, m.Object, "spec", "forProvider", "manifest")
+		}
+	}
+	return
+}
+
+// propagated returns the labels and annotations of the observed composite
+// resource that are to be propagated
+func (c *TypedComposition[XR, In]) propagated() (labels, annotations map[string]string) {
+	labels = make(map[string]string)
+	annotations = make(map[string]string)
+	if c.observed == nil || c.propagation == nil {
+		return
+	}
+
+	xr := c.observed.Resource
+	p := c.propagation
+
+	for k, v := range xr.GetLabels() {
+		if matchesKey(p.Labels, k) && !matchesKey(p.DenyLabels, k) {
+			labels[k] = v
+		}
+	}
+
+	for k, v := range xr.GetAnnotations() {
+		if matchesKey(p.Annotations, k) && !matchesKey(p.DenyAnnotations, k) {
+			annotations[k] = v
+		}
+	}
+
+	crossplane := map[string]string{
+		LabelComposite:      xr.GetName(),
+		LabelClaimName:      xr.GetLabels()[LabelClaimName],
+		LabelClaimNamespace: xr.GetLabels()[LabelClaimNamespace],
+	}
+	for k, v := range crossplane {
+		if v != "" && !matchesKey(p.DenyLabels, k) {
+			labels[k] = v
+		}
+	}
+	return
+}
+
+// setMissing sets the labels and annotations u does not have yet
+func setMissing(u *unstructured.Unstructured, labels, annotations map[string]string) {
+	if len(labels) > 0 {
+		l := u.GetLabels()
+		if l == nil {
+			l = make(map[string]string, len(labels))
+		}
+		for k, v := range labels {
+			if _, ok := l[k]; !ok {
+				l[k] = v
+			}
+		}
+		u.SetLabels(l)
+	}
+
+	if len(annotations) > 0 {
+		a := u.GetAnnotations()
+		if a == nil {
+			a = make(map[string]string, len(annotations))
+		}
+		for k, v := range annotations {
+			if _, ok := a[k]; !ok {
+				a[k] = v
+			}
+		}
+		u.SetAnnotations(a)
+	}
+}
+
+// matchesKey returns true if key is matched by any of the patterns
+func matchesKey(patterns []string, key string) bool {
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+			continue
+		}
+
+		if p == key {
+			return true
+		}
+	}
+	return false
+}
+
+// isKubernetesObject returns true if u is a provider-kubernetes Object
+func isKubernetesObject(u *unstructured.Unstructured) bool {
+	return u.GetKind() == "Object" && u.GroupVersionKind().Group == "kubernetes.crossplane.io"
+}
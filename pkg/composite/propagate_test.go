@@ -0,0 +1,138 @@
+package composite
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPropagation(t *testing.T) {
+	xr := `{
+		"apiVersion": "test.xfnlib.io/v1",
+		"kind": "XTest",
+		"metadata": {
+			"name": "xr",
+			"labels": {
+				"app": "a",
+				"team.giantswarm.io/owner": "phoenix",
+				"team.giantswarm.io/secret": "s",
+				"crossplane.io/claim-name": "claim",
+				"crossplane.io/claim-namespace": "org-a"
+			},
+			"annotations": {
+				"giantswarm.io/docs": "https://docs",
+				"kubectl.kubernetes.io/last-applied-configuration": "{}"
+			}
+		}
+	}`
+	bucket := `{"apiVersion": "s3.aws.upbound.io/v1beta1", "kind": "Bucket", "metadata": {"labels": {"app": "own"}}}`
+	object := `{
+		"apiVersion": "kubernetes.crossplane.io/v1alpha2",
+		"kind": "Object",
+		"spec": {"forProvider": {"manifest": {"apiVersion": "v1", "kind": "ConfigMap"}}}
+	}`
+	crossplane := map[string]string{
+		LabelComposite:      "xr",
+		LabelClaimName:      "claim",
+		LabelClaimNamespace: "org-a",
+	}
+
+	cases := map[string]struct {
+		propagation     *Propagation
+		object          string
+		opts            []DesiredOption
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+		wantManifest    bool
+	}{
+		"Disabled": {
+			object:     bucket,
+			wantLabels: map[string]string{"app": "own"},
+		},
+		"CrossplaneLabels": {
+			propagation: &Propagation{},
+			object:      bucket,
+			wantLabels:  merged(map[string]string{"app": "own"}, crossplane),
+		},
+		"KeepsExisting": {
+			propagation: &Propagation{Labels: []string{"app"}},
+			object:      bucket,
+			wantLabels:  merged(map[string]string{"app": "own"}, crossplane),
+		},
+		"PrefixAndDeny": {
+			propagation: &Propagation{
+				Labels:          []string{"team.giantswarm.io/*"},
+				DenyLabels:      []string{"team.giantswarm.io/secret", LabelClaimNamespace},
+				Annotations:     []string{"*"},
+				DenyAnnotations: []string{"kubectl.kubernetes.io/*"},
+			},
+			object: bucket,
+			wantLabels: map[string]string{
+				"app":                      "own",
+				"team.giantswarm.io/owner": "phoenix",
+				LabelComposite:             "xr",
+				LabelClaimName:             "claim",
+			},
+			wantAnnotations: map[string]string{"giantswarm.io/docs": "https://docs"},
+		},
+		"WithoutPropagation": {
+			propagation: &Propagation{Labels: []string{"*"}},
+			object:      bucket,
+			opts:        []DesiredOption{WithoutPropagation()},
+			wantLabels:  map[string]string{"app": "own"},
+		},
+		"KubernetesObjectManifest": {
+			propagation:     &Propagation{Annotations: []string{"giantswarm.io/*"}},
+			object:          object,
+			wantLabels:      crossplane,
+			wantAnnotations: map[string]string{"giantswarm.io/docs": "https://docs"},
+			wantManifest:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestComposition(t, testRequest(t, xr, nil, nil))
+			if tc.propagation != nil {
+				c.SetPropagation(*tc.propagation)
+			}
+
+			u := testObject(t, tc.object)
+			if err := c.AddDesired("r", u, tc.opts...); err != nil {
+				t.Fatalf("AddDesired(...): unexpected error: %v", err)
+			}
+
+			got := &c.DesiredComposed["r"].Resource.Unstructured
+			if !reflect.DeepEqual(got.GetLabels(), tc.wantLabels) {
+				t.Errorf("AddDesired(...): labels = %v, want %v", got.GetLabels(), tc.wantLabels)
+			}
+			if !reflect.DeepEqual(got.GetAnnotations(), tc.wantAnnotations) {
+				t.Errorf("AddDesired(...): annotations = %v, want %v", got.GetAnnotations(), tc.wantAnnotations)
+			}
+
+			if tc.wantManifest {
+				manifest, _, _ := unstructured.NestedMap(got.Object, "spec", "forProvider", "manifest")
+				m := &unstructured.Unstructured{Object: manifest}
+				if !reflect.DeepEqual(m.GetLabels(), tc.wantLabels) || !reflect.DeepEqual(m.GetAnnotations(), tc.wantAnnotations) {
+					t.Errorf("AddDesired(...): manifest metadata = %v, want labels %v and annotations %v", manifest["metadata"], tc.wantLabels, tc.wantAnnotations)
+				}
+			}
+
+			if tc.propagation != nil && len(u.GetLabels()) > 1 {
+				t.Errorf("AddDesired(...): the given object was modified: %v", u.GetLabels())
+			}
+		})
+	}
+}
+
+// merged returns the union of the given maps
+func merged(maps ...map[string]string) map[string]string {
+	out := make(map[string]string)
+	for _, m := range maps {
+		for k, v := range m {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,108 @@
+package composite
+
+import (
+	"slices"
+
+	"github.com/crossplane/function-sdk-go/resource"
+)
+
+// PruneOption configures the behaviour of `Prune`
+type PruneOption func(*pruneOptions)
+
+type pruneOptions struct {
+	owned func(resource.Name) bool
+}
+
+// PruneMatching limits pruning to the resources this function owns
+//
+// Use this where later steps in the pipeline compose resources of their own so
+// that these are not reported as stale.
+//
+//   - `owned` Returns true for pipeline names produced by this function
+func PruneMatching(owned func(resource.Name) bool) PruneOption {
+	return func(o *pruneOptions) {
+		o.owned = owned
+	}
+}
+
+// PruneResult lists the composed resources `Prune` found the function no
+// longer produces
+type PruneResult struct {
+	// Removed holds the resources that were desired and have been removed
+	// from the desired composed resources
+	Removed []resource.Name
+
+	// Stale holds the observed resources that no step in the pipeline has
+	// desired so far. Crossplane deletes these unless a later step desires
+	// them
+	Stale []resource.Name
+}
+
+// RemoveDesired removes a resource from the desired composed resources
+//
+//   - `n` The pipeline name of the resource to remove
+func (c *TypedComposition[XR, In]) RemoveDesired(n string) {
+	delete(c.DesiredComposed, resource.Name(n))
+	delete(c.generated, resource.Name(n))
+	delete(c.dependencies, resource.Name(n))
+}
+
+// Prune drops composed resources the function stopped producing
+//
+// A resource is stale when it was neither added through `AddDesired` during
+// this run nor handed to this function by an earlier step in the pipeline.
+// Stale entries written to `DesiredComposed` directly are removed and reported
+// as `Removed`. Observed resources that are not desired are reported as
+// `Stale` so the function can log what Crossplane is about to delete.
+//
+// Usages declared with `Uses` are managed by `ToResponse` and never pruned
+// here. Where later steps in the pipeline compose resources of their own, pass
+// `PruneMatching` so that only the resources of this function are considered.
+//
+// Prune should be called once the function has added all of its resources and
+// before `ToResponse`. Both lists are sorted by pipeline name.
+func (c *TypedComposition[XR, In]) Prune(opts ...PruneOption) (result PruneResult) {
+	options := &pruneOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	candidates := make(map[resource.Name]struct{}, len(c.ObservedComposed)+len(c.DesiredComposed))
+	for n := range c.ObservedComposed {
+		candidates[n] = struct{}{}
+	}
+	for n := range c.DesiredComposed {
+		candidates[n] = struct{}{}
+	}
+
+	for n := range candidates {
+		if _, ok := c.generated[n]; ok {
+			continue
+		}
+
+		// Owned by an earlier step in the pipeline
+		if _, ok := c.upstream[n]; ok {
+			continue
+		}
+
+		// Usages are added or pruned by ToResponse
+		if c.declaresUsage(n) {
+			continue
+		}
+
+		if options.owned != nil && !options.owned(n) {
+			continue
+		}
+
+		if _, ok := c.DesiredComposed[n]; ok {
+			delete(c.DesiredComposed, n)
+			result.Removed = append(result.Removed, n)
+			continue
+		}
+		result.Stale = append(result.Stale, n)
+	}
+
+	slices.Sort(result.Removed)
+	slices.Sort(result.Stale)
+	return
+}
@@ -0,0 +1,105 @@
+package composite
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/function-sdk-go/resource"
+	"github.com/crossplane/function-sdk-go/resource/composed"
+)
+
+func TestPrune(t *testing.T) {
+	object := `{"apiVersion": "test.xfnlib.io/v1", "kind": "Resource", "metadata": {"name": "r"}}`
+	owned := PruneMatching(func(n resource.Name) bool {
+		return strings.HasPrefix(string(n), "fn-")
+	})
+
+	cases := map[string]struct {
+		observed    []string
+		upstream    []string
+		add         []string
+		direct      []string
+		opts        []PruneOption
+		want        PruneResult
+		wantDesired []string
+	}{
+		"KeepsAdded": {
+			observed:    []string{"a"},
+			add:         []string{"a"},
+			wantDesired: []string{"a"},
+		},
+		"KeepsUpstream": {
+			observed:    []string{"a"},
+			upstream:    []string{"a"},
+			wantDesired: []string{"a"},
+		},
+		"ReportsStaleObserved": {
+			observed:    []string{"a", "b"},
+			add:         []string{"a"},
+			want:        PruneResult{Stale: []resource.Name{"b"}},
+			wantDesired: []string{"a"},
+		},
+		"RemovesDesiredNotAdded": {
+			observed:    []string{"a", "b"},
+			add:         []string{"a"},
+			direct:      []string{"b"},
+			want:        PruneResult{Removed: []resource.Name{"b"}},
+			wantDesired: []string{"a"},
+		},
+		"OnlyOwned": {
+			observed: []string{"fn-a", "other"},
+			direct:   []string{"fn-b", "later"},
+			opts:     []PruneOption{owned},
+			want: PruneResult{
+				Removed: []resource.Name{"fn-b"},
+				Stale:   []resource.Name{"fn-a"},
+			},
+			wantDesired: []string{"later"},
+		},
+		"KeepsAddedOwned": {
+			add:         []string{"fn-a"},
+			opts:        []PruneOption{owned},
+			wantDesired: []string{"fn-a"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			observed := make(map[string]string, len(tc.observed))
+			for _, n := range tc.observed {
+				observed[n] = object
+			}
+			upstream := make(map[string]string, len(tc.upstream))
+			for _, n := range tc.upstream {
+				upstream[n] = object
+			}
+
+			c := newTestComposition(t, testRequest(t, "", observed, upstream))
+			for _, n := range tc.add {
+				if err := c.AddDesired(n, testObject(t, object)); err != nil {
+					t.Fatalf("AddDesired(%q, ...): unexpected error: %v", n, err)
+				}
+			}
+			for _, n := range tc.direct {
+				c.DesiredComposed[resource.Name(n)] = &resource.DesiredComposed{
+					Resource: &composed.Unstructured{Unstructured: *testObject(t, object)},
+				}
+			}
+
+			if got := c.Prune(tc.opts...); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Prune(...) = %+v, want %+v", got, tc.want)
+			}
+
+			var desired []string
+			for n := range c.DesiredComposed {
+				desired = append(desired, string(n))
+			}
+			slices.Sort(desired)
+			if !slices.Equal(desired, tc.wantDesired) {
+				t.Errorf("Prune(...): desired = %v, want %v", desired, tc.wantDesired)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package composite
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/function-sdk-go/resource"
+	"github.com/crossplane/function-sdk-go/resource/composed"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ReadinessCheck derives the readiness of a desired composed resource from its
+// observed counterpart.
+//
+// `observed` is nil when the resource does not exist in the cluster yet.
+type ReadinessCheck func(observed *composed.Unstructured) resource.Ready
+
+// WithReadiness sets the readiness strategy used for a single resource added
+// through `AddDesired`
+//
+// Without this option resources are always reported as ready.
+func WithReadiness(check ReadinessCheck) DesiredOption {
+	return func(o *desiredOptions) {
+		o.readiness = check
+	}
+}
+
+// ReadyAlways marks the resource as ready regardless of its observed state.
+//
+// This is the default for `AddDesired`.
+func ReadyAlways() ReadinessCheck {
+	return func(_ *composed.Unstructured) resource.Ready {
+		return resource.ReadyTrue
+	}
+}
+
+// ReadyUnspecified leaves the readiness of the resource to Crossplane
+func ReadyUnspecified() ReadinessCheck {
+	return func(_ *composed.Unstructured) resource.Ready {
+		return resource.ReadyUnspecified
+	}
+}
+
+// ReadyFromObserved marks the resource as ready once the observed resource
+// reports a `Ready` condition with status `True`
+func ReadyFromObserved() ReadinessCheck {
+	return ReadyMatchingCondition(xpv1.TypeReady)
+}
+
+// ReadyMatchingCondition marks the resource as ready once the observed
+// resource reports the condition `ct` with status `True`
+func ReadyMatchingCondition(ct xpv1.ConditionType) ReadinessCheck {
+	return func(observed *composed.Unstructured) resource.Ready {
+		if observed == nil {
+			return resource.ReadyFalse
+		}
+
+		if observed.GetCondition(ct).Status != corev1.ConditionTrue {
+			return resource.ReadyFalse
+		}
+		return resource.ReadyTrue
+	}
+}
+
+// ReadyWhenFieldExists marks the resource as ready once `path` is set on the
+// observed resource
+//
+//   - `path` A field path such as `status.atProvider.arn`
+func ReadyWhenFieldExists(path string) ReadinessCheck {
+	return func(observed *composed.Unstructured) resource.Ready {
+		if observed == nil {
+			return resource.ReadyFalse
+		}
+
+		if _, err := observed.GetValue(path); err != nil {
+			return resource.ReadyFalse
+		}
+		return resource.ReadyTrue
+	}
+}
+
+// ReadyWhenFieldEquals marks the resource as ready once `path` on the observed
+// resource holds `expected`
+//
+// Values are compared after being passed through `json.Marshal` so that a Go
+// `int` matches the numbers found on the observed resource.
+//
+//   - `path` A field path such as `status.atProvider.state`
+//   - `expected` The value the field must hold
+func ReadyWhenFieldEquals(path string, expected any) ReadinessCheck {
+	return func(observed *composed.Unstructured) resource.Ready {
+		if observed == nil {
+			return resource.ReadyFalse
+		}
+
+		var (
+			value, got, want any
+			err              error
+		)
+		if value, err = observed.GetValue(path); err != nil {
+			return resource.ReadyFalse
+		}
+
+		if err = To(value, &got); err != nil {
+			return resource.ReadyFalse
+		}
+
+		if err = To(expected, &want); err != nil {
+			return resource.ReadyFalse
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			return resource.ReadyFalse
+		}
+		return resource.ReadyTrue
+	}
+}
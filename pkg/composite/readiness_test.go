@@ -0,0 +1,120 @@
+package composite
+
+import (
+	"testing"
+
+	"github.com/crossplane/function-sdk-go/resource"
+)
+
+func TestReadiness(t *testing.T) {
+	ready := `{
+		"apiVersion": "s3.aws.upbound.io/v1beta1",
+		"kind": "Bucket",
+		"metadata": {"name": "bucket"},
+		"status": {
+			"atProvider": {"arn": "arn:aws:s3:::bucket", "objects": 2},
+			"conditions": [
+				{"type": "Ready", "status": "True"},
+				{"type": "Synced", "status": "False"}
+			]
+		}
+	}`
+	creating := `{
+		"apiVersion": "s3.aws.upbound.io/v1beta1",
+		"kind": "Bucket",
+		"metadata": {"name": "bucket"},
+		"status": {"conditions": [{"type": "Ready", "status": "False"}]}
+	}`
+
+	cases := map[string]struct {
+		observed string
+		opts     []DesiredOption
+		want     resource.Ready
+	}{
+		"Default": {
+			want: resource.ReadyTrue,
+		},
+		"Always": {
+			opts: []DesiredOption{WithReadiness(ReadyAlways())},
+			want: resource.ReadyTrue,
+		},
+		"Unspecified": {
+			observed: ready,
+			opts:     []DesiredOption{WithReadiness(ReadyUnspecified())},
+			want:     resource.ReadyUnspecified,
+		},
+		"FromObservedReady": {
+			observed: ready,
+			opts:     []DesiredOption{WithReadiness(ReadyFromObserved())},
+			want:     resource.ReadyTrue,
+		},
+		"FromObservedNotReady": {
+			observed: creating,
+			opts:     []DesiredOption{WithReadiness(ReadyFromObserved())},
+			want:     resource.ReadyFalse,
+		},
+		"FromObservedNotObserved": {
+			opts: []DesiredOption{WithReadiness(ReadyFromObserved())},
+			want: resource.ReadyFalse,
+		},
+		"MatchingConditionFalse": {
+			observed: ready,
+			opts:     []DesiredOption{WithReadiness(ReadyMatchingCondition("Synced"))},
+			want:     resource.ReadyFalse,
+		},
+		"FieldExists": {
+			observed: ready,
+			opts:     []DesiredOption{WithReadiness(ReadyWhenFieldExists("status.atProvider.arn"))},
+			want:     resource.ReadyTrue,
+		},
+		"FieldMissing": {
+			observed: creating,
+			opts:     []DesiredOption{WithReadiness(ReadyWhenFieldExists("status.atProvider.arn"))},
+			want:     resource.ReadyFalse,
+		},
+		"FieldEqualsInt": {
+			observed: ready,
+			opts:     []DesiredOption{WithReadiness(ReadyWhenFieldEquals("status.atProvider.objects", 2))},
+			want:     resource.ReadyTrue,
+		},
+		"FieldDiffers": {
+			observed: ready,
+			opts:     []DesiredOption{WithReadiness(ReadyWhenFieldEquals("status.atProvider.objects", 3))},
+			want:     resource.ReadyFalse,
+		},
+		"FieldEqualsNotObserved": {
+			opts: []DesiredOption{WithReadiness(ReadyWhenFieldEquals("status.atProvider.objects", 2))},
+			want: resource.ReadyFalse,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var observed map[string]string
+			if tc.observed != "" {
+				observed = map[string]string{"r": tc.observed}
+			}
+
+			c := newTestComposition(t, testRequest(t, "", observed, nil))
+			if err := c.AddDesired("r", testObject(t, testDesiredBucket), tc.opts...); err != nil {
+				t.Fatalf("AddDesired(...): unexpected error: %v", err)
+			}
+
+			if got := c.DesiredComposed["r"].Ready; got != tc.want {
+				t.Errorf("AddDesired(...): ready = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReadinessRefreshedWhenUnchanged(t *testing.T) {
+	c := newTestComposition(t, testRequest(t, "", nil, map[string]string{"r": testDesiredBucket}))
+
+	if err := c.AddDesired("r", testObject(t, testDesiredBucket), WithReadiness(ReadyFromObserved())); err != nil {
+		t.Fatalf("AddDesired(...): unexpected error: %v", err)
+	}
+
+	if got := c.DesiredComposed["r"].Ready; got != resource.ReadyFalse {
+		t.Errorf("AddDesired(...): ready = %v, want %v", got, resource.ReadyFalse)
+	}
+}
@@ -0,0 +1,176 @@
+package composite
+
+import (
+	"fmt"
+
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Target selects the resources a result or condition is reported on
+type Target int
+
+const (
+	// TargetComposite reports on the composite resource only
+	TargetComposite Target = iota
+
+	// TargetCompositeAndClaim reports on the composite resource and its claim
+	TargetCompositeAndClaim
+)
+
+// Result is an event emitted by the function
+type Result struct {
+	// Severity of the result. Fatal results stop the pipeline
+	Severity fnv1.Severity
+
+	// Reason is a short CamelCase reason for the result
+	Reason string
+
+	// Message is the human readable message of the result
+	Message string
+
+	// Target is the resource the result is reported on
+	Target Target
+}
+
+// Condition is a status condition the function sets on the composite resource
+type Condition struct {
+	// Type of the condition, for example `DatabaseReady`
+	Type string
+
+	// Status of the condition
+	Status corev1.ConditionStatus
+
+	// Reason is a short CamelCase reason for the condition
+	Reason string
+
+	// Message is the human readable message of the condition
+	Message string
+
+	// Target is the resource the condition is reported on
+	Target Target
+}
+
+// AddResult records a result to be emitted by `ToResponse`
+//
+// Results are emitted in the order they are added. Duplicate results are only
+// emitted once.
+func (c *TypedComposition[XR, In]) AddResult(r Result) {
+	for _, e := range c.results {
+		if e == r {
+			return
+		}
+	}
+	c.results = append(c.results, r)
+}
+
+// Normal records a result with normal severity
+func (c *TypedComposition[XR, In]) Normal(reason, message string) {
+	c.AddResult(Result{
+		Severity: fnv1.Severity_SEVERITY_NORMAL,
+		Reason:   reason,
+		Message:  message,
+	})
+}
+
+// Normalf records a result with normal severity and a formatted message
+func (c *TypedComposition[XR, In]) Normalf(reason, format string, a ...any) {
+	c.Normal(reason, fmt.Sprintf(format, a...))
+}
+
+// Warning records a result with warning severity
+//
+// Nothing is recorded when err is nil.
+func (c *TypedComposition[XR, In]) Warning(reason string, err error) {
+	if err == nil {
+		return
+	}
+
+	c.AddResult(Result{
+		Severity: fnv1.Severity_SEVERITY_WARNING,
+		Reason:   reason,
+		Message:  err.Error(),
+	})
+}
+
+// SetCondition records a condition to be emitted by `ToResponse`
+//
+// Setting a condition of a type that has already been set replaces the
+// earlier condition.
+func (c *TypedComposition[XR, In]) SetCondition(cond Condition) {
+	for i, e := range c.conditions {
+		if e.Type == cond.Type {
+			c.conditions[i] = cond
+			return
+		}
+	}
+	c.conditions = append(c.conditions, cond)
+}
+
+// setResults writes the collected results and conditions to the response
+//
+// Entries already present on the response are not added again.
+func (c *TypedComposition[XR, In]) setResults(r *fnv1.RunFunctionResponse) {
+	for _, e := range c.results {
+		result := &fnv1.Result{
+			Severity: e.Severity,
+			Message:  e.Message,
+			Target:   e.Target.proto(),
+		}
+		if e.Reason != "" {
+			result.Reason = proto.String(e.Reason)
+		}
+
+		if !containsEqual(r.GetResults(), result) {
+			r.Results = append(r.Results, result)
+		}
+	}
+
+	for _, e := range c.conditions {
+		condition := &fnv1.Condition{
+			Type:   e.Type,
+			Status: conditionStatus(e.Status),
+			Reason: e.Reason,
+			Target: e.Target.proto(),
+		}
+		if e.Message != "" {
+			condition.Message = proto.String(e.Message)
+		}
+
+		if !containsEqual(r.GetConditions(), condition) {
+			r.Conditions = append(r.Conditions, condition)
+		}
+	}
+}
+
+// proto converts the target to its protobuf representation
+func (t Target) proto() *fnv1.Target {
+	if t == TargetCompositeAndClaim {
+		return fnv1.Target_TARGET_COMPOSITE_AND_CLAIM.Enum()
+	}
+	return fnv1.Target_TARGET_COMPOSITE.Enum()
+}
+
+// conditionStatus converts a kubernetes condition status to its protobuf
+// representation
+func conditionStatus(s corev1.ConditionStatus) fnv1.Status {
+	switch s {
+	case corev1.ConditionTrue:
+		return fnv1.Status_STATUS_CONDITION_TRUE
+	case corev1.ConditionFalse:
+		return fnv1.Status_STATUS_CONDITION_FALSE
+	default:
+		return fnv1.Status_STATUS_CONDITION_UNKNOWN
+	}
+}
+
+// containsEqual returns true if list holds a message equal to m
+func containsEqual[M proto.Message](list []M, m M) bool {
+	for _, e := range list {
+		if proto.Equal(e, m) {
+			return true
+		}
+	}
+	return false
+}
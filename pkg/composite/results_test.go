@@ -0,0 +1,145 @@
+package composite
+
+import (
+	"errors"
+	"testing"
+
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"github.com/crossplane/function-sdk-go/response"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestResults(t *testing.T) {
+	cases := map[string]struct {
+		existing []*fnv1.Result
+		record   func(c *testComposition)
+		want     []*fnv1.Result
+	}{
+		"InOrder": {
+			record: func(c *testComposition) {
+				c.Normal("Created", "created")
+				c.Warning("Throttled", errors.New("throttled"))
+				c.Normalf("Scaled", "scaled to %d", 3)
+			},
+			want: []*fnv1.Result{
+				{Severity: fnv1.Severity_SEVERITY_NORMAL, Reason: proto.String("Created"), Message: "created", Target: fnv1.Target_TARGET_COMPOSITE.Enum()},
+				{Severity: fnv1.Severity_SEVERITY_WARNING, Reason: proto.String("Throttled"), Message: "throttled", Target: fnv1.Target_TARGET_COMPOSITE.Enum()},
+				{Severity: fnv1.Severity_SEVERITY_NORMAL, Reason: proto.String("Scaled"), Message: "scaled to 3", Target: fnv1.Target_TARGET_COMPOSITE.Enum()},
+			},
+		},
+		"Duplicates": {
+			record: func(c *testComposition) {
+				c.Normal("Created", "created")
+				c.Normal("Created", "created")
+			},
+			want: []*fnv1.Result{
+				{Severity: fnv1.Severity_SEVERITY_NORMAL, Reason: proto.String("Created"), Message: "created", Target: fnv1.Target_TARGET_COMPOSITE.Enum()},
+			},
+		},
+		"NilWarning": {
+			record: func(c *testComposition) {
+				c.Warning("Throttled", nil)
+			},
+		},
+		"ClaimWithoutReason": {
+			record: func(c *testComposition) {
+				c.AddResult(Result{Severity: fnv1.Severity_SEVERITY_NORMAL, Message: "ready", Target: TargetCompositeAndClaim})
+			},
+			want: []*fnv1.Result{
+				{Severity: fnv1.Severity_SEVERITY_NORMAL, Message: "ready", Target: fnv1.Target_TARGET_COMPOSITE_AND_CLAIM.Enum()},
+			},
+		},
+		"AlreadyOnResponse": {
+			existing: []*fnv1.Result{
+				{Severity: fnv1.Severity_SEVERITY_NORMAL, Reason: proto.String("Created"), Message: "created", Target: fnv1.Target_TARGET_COMPOSITE.Enum()},
+			},
+			record: func(c *testComposition) {
+				c.Normal("Created", "created")
+			},
+			want: []*fnv1.Result{
+				{Severity: fnv1.Severity_SEVERITY_NORMAL, Reason: proto.String("Created"), Message: "created", Target: fnv1.Target_TARGET_COMPOSITE.Enum()},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := testRequest(t, "", nil, nil)
+			c := newTestComposition(t, req)
+			tc.record(c)
+
+			rsp := response.To(req, response.DefaultTTL)
+			rsp.Results = tc.existing
+			if err := c.ToResponse(rsp); err != nil {
+				t.Fatalf("ToResponse(...): unexpected error: %v", err)
+			}
+
+			got := rsp.GetResults()
+			if len(got) != len(tc.want) {
+				t.Fatalf("ToResponse(...): results = %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if !proto.Equal(got[i], tc.want[i]) {
+					t.Errorf("ToResponse(...): result %d = %v, want %v", i, got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestConditions(t *testing.T) {
+	cases := map[string]struct {
+		conditions []Condition
+		want       []*fnv1.Condition
+	}{
+		"Statuses": {
+			conditions: []Condition{
+				{Type: "DatabaseReady", Status: corev1.ConditionTrue, Reason: "Available"},
+				{Type: "NetworkReady", Status: corev1.ConditionFalse, Reason: "Creating", Message: "waiting for vpc"},
+				{Type: "DNSReady", Status: corev1.ConditionUnknown, Reason: "Unknown", Target: TargetCompositeAndClaim},
+			},
+			want: []*fnv1.Condition{
+				{Type: "DatabaseReady", Status: fnv1.Status_STATUS_CONDITION_TRUE, Reason: "Available", Target: fnv1.Target_TARGET_COMPOSITE.Enum()},
+				{Type: "NetworkReady", Status: fnv1.Status_STATUS_CONDITION_FALSE, Reason: "Creating", Message: proto.String("waiting for vpc"), Target: fnv1.Target_TARGET_COMPOSITE.Enum()},
+				{Type: "DNSReady", Status: fnv1.Status_STATUS_CONDITION_UNKNOWN, Reason: "Unknown", Target: fnv1.Target_TARGET_COMPOSITE_AND_CLAIM.Enum()},
+			},
+		},
+		"ReplacesType": {
+			conditions: []Condition{
+				{Type: "DatabaseReady", Status: corev1.ConditionFalse, Reason: "Creating"},
+				{Type: "NetworkReady", Status: corev1.ConditionTrue, Reason: "Available"},
+				{Type: "DatabaseReady", Status: corev1.ConditionTrue, Reason: "Available"},
+			},
+			want: []*fnv1.Condition{
+				{Type: "DatabaseReady", Status: fnv1.Status_STATUS_CONDITION_TRUE, Reason: "Available", Target: fnv1.Target_TARGET_COMPOSITE.Enum()},
+				{Type: "NetworkReady", Status: fnv1.Status_STATUS_CONDITION_TRUE, Reason: "Available", Target: fnv1.Target_TARGET_COMPOSITE.Enum()},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := testRequest(t, "", nil, nil)
+			c := newTestComposition(t, req)
+			for _, cond := range tc.conditions {
+				c.SetCondition(cond)
+			}
+
+			rsp := response.To(req, response.DefaultTTL)
+			if err := c.ToResponse(rsp); err != nil {
+				t.Fatalf("ToResponse(...): unexpected error: %v", err)
+			}
+
+			got := rsp.GetConditions()
+			if len(got) != len(tc.want) {
+				t.Fatalf("ToResponse(...): conditions = %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if !proto.Equal(got[i], tc.want[i]) {
+					t.Errorf("ToResponse(...): condition %d = %v, want %v", i, got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package composite
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"github.com/crossplane/function-sdk-go/response"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// WaitingTTL is the default TTL of responses for compositions waiting on a
+// spec, a composed resource or extra resources
+const WaitingTTL = 10 * time.Second
+
+// Handler is the body of a composition function run by `Run`
+type Handler[XR any, In InputProvider] func(ctx context.Context, c *TypedComposition[XR, In]) error
+
+// WithWaitingTTL sets the TTL `Run` uses when the handler returns one of the
+// waiting errors. Defaults to `WaitingTTL`
+func WithWaitingTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.waitingTTL = ttl
+	}
+}
+
+// Run runs a composition function handler and builds the response
+//
+// Run reads the request with `NewTyped`, calls `handler` and writes the
+// composition back with `ToResponse`. Errors are mapped to results by their
+// `ErrorSeverity`:
+//
+//   - Fatal errors, including any error that does not implement
+//     `SeverityError`, produce a fatal result
+//   - Warning errors produce a warning result. The desired state built so far
+//     is kept
+//   - Normal errors produce a normal result and keep the desired state. Errors
+//     matching `ErrWaiting`, such as `WaitingForSpec`, `WaitingForResource`
+//     and `WaitingForExtraResources`, also requeue after the waiting TTL
+//   - A panic in the handler is recovered into a fatal result
+//
+// Use `WithSeverity` to change the result an error produces.
+//
+// Wrap `handler` with `OnDelete` to call cleanup hooks instead of it while the
+// composite resource is deleted.
+//
+// The returned error is always nil so Run can be returned from `RunFunction`
+// directly. `opts` are passed to `NewTyped`.
+//
+// Example:
+//
+//	func (f *Function) RunFunction(ctx context.Context, req *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
+//		return composite.Run(ctx, req, &v1beta1.Input{}, f.compose,
+//			composite.WithLogger(f.log),
+//		)
+//	}
+//
+//	func (f *Function) compose(ctx context.Context, c *composite.TypedComposition[v1beta1.XCluster, *v1beta1.Input]) error {
+//		...
+//	}
+func Run[XR any, In InputProvider](ctx context.Context, req *fnv1.RunFunctionRequest, input In, handler Handler[XR, In], opts ...Option) (rsp *fnv1.RunFunctionResponse, err error) {
+	o := newOptions(opts...)
+	rsp = response.To(req, response.DefaultTTL)
+
+	defer func() {
+		if r := recover(); r != nil {
+			if o.log != nil {
+				o.log.Info("Function panicked", "panic", r, "stack", string(debug.Stack()))
+			}
+			response.Fatal(rsp, errors.Errorf("function panicked: %v", r))
+			err = nil
+		}
+	}()
+
+	var c *TypedComposition[XR, In]
+	if c, err = NewTyped[XR](req, input, opts...); err != nil {
+		return respond[XR, In](rsp, nil, errors.Wrap(err, "cannot read request"), o)
+	}
+
+	return respond(rsp, c, handler(ctx, c), o)
+}
+
+// respond writes the composition and the outcome of the handler to rsp
+//
+// `c` is nil when the request could not be read.
+func respond[XR any, In InputProvider](rsp *fnv1.RunFunctionResponse, c *TypedComposition[XR, In], err error, o *options) (*fnv1.RunFunctionResponse, error) {
+	severity := ErrorSeverity(err)
+	if err != nil && o.log != nil {
+		o.log.Info("Function returned an error", "error", err, "severity", severity)
+	}
+
+	if err != nil && (severity == fnv1.Severity_SEVERITY_FATAL || c == nil) {
+		response.Fatal(rsp, err)
+		return rsp, nil
+	}
+
+	if rerr := c.ToResponse(rsp); rerr != nil {
+		response.Fatal(rsp, errors.Wrap(rerr, "cannot convert composition to response"))
+		return rsp, nil
+	}
+
+	switch {
+	case err == nil:
+		response.Normal(rsp, "Successful run")
+	case severity == fnv1.Severity_SEVERITY_WARNING:
+		response.Warning(rsp, err)
+	default:
+		response.Normal(rsp, err.Error())
+		if errors.Is(err, ErrWaiting) {
+			if rsp.Meta == nil {
+				rsp.Meta = &fnv1.ResponseMeta{}
+			}
+			rsp.Meta.Ttl = durationpb.New(o.waitingTTL)
+		}
+	}
+	return rsp, nil
+}
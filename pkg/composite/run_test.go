@@ -0,0 +1,117 @@
+package composite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"github.com/crossplane/function-sdk-go/response"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRun(t *testing.T) {
+	object := `{"apiVersion": "test.xfnlib.io/v1", "kind": "Resource", "metadata": {"name": "r"}}`
+
+	cases := map[string]struct {
+		err          error
+		panic        bool
+		opts         []Option
+		wantSeverity fnv1.Severity
+		wantDesired  bool
+		wantTTL      time.Duration
+	}{
+		"Success": {
+			wantSeverity: fnv1.Severity_SEVERITY_NORMAL,
+			wantDesired:  true,
+			wantTTL:      response.DefaultTTL,
+		},
+		"Waiting": {
+			err:          &WaitingForResource{Name: "db"},
+			wantSeverity: fnv1.Severity_SEVERITY_NORMAL,
+			wantDesired:  true,
+			wantTTL:      WaitingTTL,
+		},
+		"WaitingCustomTTL": {
+			err:          &WaitingForExtraResources{Key: "vpc"},
+			opts:         []Option{WithWaitingTTL(time.Second)},
+			wantSeverity: fnv1.Severity_SEVERITY_NORMAL,
+			wantDesired:  true,
+			wantTTL:      time.Second,
+		},
+		"Warning": {
+			err:          WithSeverity(errors.New("lookup failed"), fnv1.Severity_SEVERITY_WARNING),
+			wantSeverity: fnv1.Severity_SEVERITY_WARNING,
+			wantDesired:  true,
+			wantTTL:      response.DefaultTTL,
+		},
+		"Plain": {
+			err:          errors.New("boom"),
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+			wantTTL:      response.DefaultTTL,
+		},
+		"Panic": {
+			panic:        true,
+			wantSeverity: fnv1.Severity_SEVERITY_FATAL,
+			wantTTL:      response.DefaultTTL,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := func(_ context.Context, c *testComposition) error {
+				if err := c.AddDesired("r", testObject(t, object)); err != nil {
+					t.Fatalf("AddDesired(...): unexpected error: %v", err)
+				}
+				if tc.panic {
+					panic("boom")
+				}
+				return tc.err
+			}
+
+			rsp, err := Run(context.Background(), testRequest(t, "", nil, nil), &unstructured.Unstructured{}, handler, tc.opts...)
+			if err != nil {
+				t.Fatalf("Run(...): unexpected error: %v", err)
+			}
+
+			if len(rsp.GetResults()) != 1 || rsp.GetResults()[0].GetSeverity() != tc.wantSeverity {
+				t.Errorf("Run(...): results = %v, want one of severity %v", rsp.GetResults(), tc.wantSeverity)
+			}
+
+			if _, ok := rsp.GetDesired().GetResources()["r"]; ok != tc.wantDesired {
+				t.Errorf("Run(...): desired %q = %v, want %v", "r", ok, tc.wantDesired)
+			}
+
+			if got := rsp.GetMeta().GetTtl().AsDuration(); got != tc.wantTTL {
+				t.Errorf("Run(...): ttl = %v, want %v", got, tc.wantTTL)
+			}
+		})
+	}
+}
+
+func TestRunDecodeFailure(t *testing.T) {
+	type xr struct {
+		Spec struct {
+			Region int `json:"region"`
+		} `json:"spec"`
+	}
+
+	called := false
+	handler := func(context.Context, *TypedComposition[xr, *unstructured.Unstructured]) error {
+		called = true
+		return nil
+	}
+
+	rsp, err := Run(context.Background(), testRequest(t, "", nil, nil), &unstructured.Unstructured{}, handler)
+	if err != nil {
+		t.Fatalf("Run(...): unexpected error: %v", err)
+	}
+
+	if called {
+		t.Error("Run(...): handler called although the request could not be read")
+	}
+	if len(rsp.GetResults()) != 1 || rsp.GetResults()[0].GetSeverity() != fnv1.Severity_SEVERITY_FATAL {
+		t.Errorf("Run(...): results = %v, want one fatal result", rsp.GetResults())
+	}
+}
@@ -0,0 +1,153 @@
+package composite
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const statusField = "status"
+
+// SetStatus merges a status object into the status of the desired composite
+// resource
+//
+// `v` is the status itself and not the composite resource. Fields that are
+// already set on the desired status are kept unless `v` sets them too, so
+// status set by earlier steps in the pipeline is not lost.
+//
+// Example:
+//
+//	err := composed.SetStatus(v1beta1.XClusterStatus{
+//		VpcID: vpcID,
+//	})
+func (c *TypedComposition[XR, In]) SetStatus(v any) (err error) {
+	var status map[string]any
+	if err = To(v, &status); err != nil {
+		err = errors.Wrapf(err, "cannot convert %T to status", v)
+		return
+	}
+
+	c.mergeStatus(status)
+	return
+}
+
+// PatchStatus sets a single field on the status of the desired composite
+// resource
+//
+//   - `fieldPath` The path to set. This must start with `status`, for example
+//     `status.atProvider.vpcId`
+//   - `value` The value to set
+func (c *TypedComposition[XR, In]) PatchStatus(fieldPath string, value any) (err error) {
+	if err = validateStatusPath(fieldPath); err != nil {
+		return
+	}
+
+	var v any
+	if err = To(value, &v); err != nil {
+		err = errors.Wrapf(err, "cannot convert %T for %q", value, fieldPath)
+		return
+	}
+
+	if fieldPath == statusField {
+		status, ok := v.(map[string]any)
+		if !ok && v != nil {
+			err = errors.Errorf("cannot set %q to %T", fieldPath, value)
+			return
+		}
+		c.mergeStatus(status)
+		return
+	}
+
+	if err = c.DesiredComposite.Resource.SetValue(fieldPath, v); err != nil {
+		err = errors.Wrapf(err, "cannot set %q on desired composite", fieldPath)
+	}
+	return
+}
+
+// CarryOverStatus copies fields from the observed composite status into the
+// desired composite status
+//
+// Fields already set on the desired status are not overwritten. Use this to
+// stop status fields the function does not compute on every run from
+// flickering.
+//
+//   - `paths` The fields to copy, each starting with `status`. If no paths are
+//     given, the whole observed status is copied except for `conditions`
+//     which are owned by Crossplane
+func (c *TypedComposition[XR, In]) CarryOverStatus(paths ...string) (err error) {
+	if c.observed == nil {
+		return
+	}
+
+	if len(paths) == 0 {
+		observed, _ := c.observed.Resource.Object[statusField].(map[string]any)
+		observed = runtime.DeepCopyJSON(observed)
+		delete(observed, "conditions")
+
+		// The desired status may have been set with any Go type
+		var desired map[string]any
+		if err = To(c.DesiredComposite.Resource.Object[statusField], &desired); err != nil {
+			err = errors.Wrap(err, "cannot read status of desired composite")
+			return
+		}
+
+		if merged := mergeMaps(observed, desired); len(merged) > 0 {
+			c.DesiredComposite.Resource.Object[statusField] = merged
+		}
+		return
+	}
+
+	desired := fieldpath.Pave(c.DesiredComposite.Resource.Object)
+	for _, p := range paths {
+		if err = validateStatusPath(p); err != nil {
+			return
+		}
+
+		if _, err = desired.GetValue(p); err == nil {
+			continue
+		} else if !fieldpath.IsNotFound(err) {
+			err = errors.Wrapf(err, "cannot read %q from desired composite", p)
+			return
+		}
+
+		var v any
+		if v, err = c.observed.Resource.GetValue(p); err != nil {
+			if fieldpath.IsNotFound(err) {
+				err = nil
+				continue
+			}
+			err = errors.Wrapf(err, "cannot read %q from observed composite", p)
+			return
+		}
+
+		if err = desired.SetValue(p, runtime.DeepCopyJSONValue(v)); err != nil {
+			err = errors.Wrapf(err, "cannot set %q on desired composite", p)
+			return
+		}
+	}
+	return
+}
+
+// mergeStatus merges status into the desired composite status
+func (c *TypedComposition[XR, In]) mergeStatus(status map[string]any) {
+	if len(status) == 0 {
+		return
+	}
+
+	desired, _ := c.DesiredComposite.Resource.Object[statusField].(map[string]any)
+	c.DesiredComposite.Resource.Object[statusField] = mergeMaps(desired, status)
+}
+
+// validateStatusPath ensures that fieldPath points into the status object
+func validateStatusPath(fieldPath string) (err error) {
+	var segments fieldpath.Segments
+	if segments, err = fieldpath.Parse(fieldPath); err != nil {
+		err = errors.Wrapf(err, "cannot parse field path %q", fieldPath)
+		return
+	}
+
+	if len(segments) == 0 || segments[0].Type != fieldpath.SegmentField || segments[0].Field != statusField {
+		err = &InvalidStatusPath{Path: fieldPath}
+	}
+	return
+}
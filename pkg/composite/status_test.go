@@ -0,0 +1,213 @@
+package composite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCarryOverStatus(t *testing.T) {
+	xr := `{
+		"apiVersion": "test.xfnlib.io/v1",
+		"kind": "XTest",
+		"metadata": {"name": "xr"},
+		"status": {
+			"vpcId": "vpc-1",
+			"count": 1,
+			"nested": {"a": "observed", "b": "observed"},
+			"conditions": [{"type": "Ready", "status": "True"}]
+		}
+	}`
+
+	cases := map[string]struct {
+		desired map[string]any
+		paths   []string
+		want    map[string]any
+	}{
+		"CopiesWholeStatusWithoutConditions": {
+			want: map[string]any{
+				"vpcId":  "vpc-1",
+				"count":  float64(1),
+				"nested": map[string]any{"a": "observed", "b": "observed"},
+			},
+		},
+		"KeepsDesiredFieldsOfAnyType": {
+			desired: map[string]any{
+				"count":  3,
+				"nested": map[string]any{"a": "desired"},
+			},
+			want: map[string]any{
+				"vpcId":  "vpc-1",
+				"count":  float64(3),
+				"nested": map[string]any{"a": "desired", "b": "observed"},
+			},
+		},
+		"CopiesSelectedPaths": {
+			desired: map[string]any{"count": 3},
+			paths:   []string{"status.vpcId", "status.count", "status.missing"},
+			want: map[string]any{
+				"vpcId": "vpc-1",
+				"count": 3,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestComposition(t, testRequest(t, xr, nil, nil))
+			if tc.desired != nil {
+				c.DesiredComposite.Resource.Object["status"] = tc.desired
+			}
+
+			if err := c.CarryOverStatus(tc.paths...); err != nil {
+				t.Fatalf("CarryOverStatus(...): unexpected error: %v", err)
+			}
+
+			if got := c.DesiredComposite.Resource.Object["status"]; !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("CarryOverStatus(...): status = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCarryOverStatusRejectsPathsOutsideStatus(t *testing.T) {
+	c := newTestComposition(t, testRequest(t, "", nil, nil))
+
+	err := c.CarryOverStatus("spec.region")
+	if _, ok := err.(*InvalidStatusPath); !ok {
+		t.Errorf("CarryOverStatus(...): error = %v, want *InvalidStatusPath", err)
+	}
+}
+
+func TestSetStatus(t *testing.T) {
+	type status struct {
+		VpcID string `json:"vpcId,omitempty"`
+		Count int    `json:"count,omitempty"`
+	}
+
+	cases := map[string]struct {
+		desired map[string]any
+		status  any
+		want    map[string]any
+	}{
+		"Struct": {
+			status: status{VpcID: "vpc-1"},
+			want:   map[string]any{"vpcId": "vpc-1"},
+		},
+		"KeepsEarlierFields": {
+			desired: map[string]any{"subnet": "subnet-1", "count": float64(1)},
+			status:  status{VpcID: "vpc-1", Count: 2},
+			want:    map[string]any{"subnet": "subnet-1", "vpcId": "vpc-1", "count": float64(2)},
+		},
+		"StaysInsideStatus": {
+			status: map[string]any{"spec": map[string]any{"region": "us-east-1"}},
+			want:   map[string]any{"spec": map[string]any{"region": "us-east-1"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestComposition(t, testRequest(t, "", nil, nil))
+			if tc.desired != nil {
+				c.DesiredComposite.Resource.Object["status"] = tc.desired
+			}
+
+			if err := c.SetStatus(tc.status); err != nil {
+				t.Fatalf("SetStatus(...): unexpected error: %v", err)
+			}
+
+			if got := c.DesiredComposite.Resource.Object["status"]; !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("SetStatus(...): status = %v, want %v", got, tc.want)
+			}
+			if _, ok := c.DesiredComposite.Resource.Object["spec"]; ok {
+				t.Errorf("SetStatus(...): desired spec = %v, want none", c.DesiredComposite.Resource.Object["spec"])
+			}
+		})
+	}
+}
+
+func TestPatchStatus(t *testing.T) {
+	cases := map[string]struct {
+		desired     map[string]any
+		path        string
+		value       any
+		want        map[string]any
+		wantErr     bool
+		wantInvalid bool
+	}{
+		"Field": {
+			path:  "status.atProvider.vpcId",
+			value: "vpc-1",
+			want:  map[string]any{"atProvider": map[string]any{"vpcId": "vpc-1"}},
+		},
+		"WholeStatus": {
+			desired: map[string]any{"subnet": "subnet-1"},
+			path:    "status",
+			value:   map[string]any{"vpcId": "vpc-1"},
+			want:    map[string]any{"subnet": "subnet-1", "vpcId": "vpc-1"},
+		},
+		"WholeStatusNotAnObject": {
+			path:    "status",
+			value:   "vpc-1",
+			wantErr: true,
+		},
+		"Spec": {
+			path:        "spec.region",
+			value:       "us-east-1",
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		"Metadata": {
+			path:        "metadata.labels",
+			value:       map[string]any{"a": "b"},
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		"StatusPrefix": {
+			path:        "statuses.vpcId",
+			value:       "vpc-1",
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		"Index": {
+			path:        "[0]",
+			value:       "vpc-1",
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		"Unparsable": {
+			path:    "status[",
+			value:   "vpc-1",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestComposition(t, testRequest(t, "", nil, nil))
+			if tc.desired != nil {
+				c.DesiredComposite.Resource.Object["status"] = tc.desired
+			}
+
+			err := c.PatchStatus(tc.path, tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("PatchStatus(%q, ...): error = %v, want error %v", tc.path, err, tc.wantErr)
+			}
+			if _, ok := err.(*InvalidStatusPath); ok != tc.wantInvalid {
+				t.Errorf("PatchStatus(%q, ...): error = %v, want *InvalidStatusPath %v", tc.path, err, tc.wantInvalid)
+			}
+
+			for _, field := range []string{"spec", "metadata"} {
+				if _, ok := c.DesiredComposite.Resource.Object[field]; ok {
+					t.Errorf("PatchStatus(%q, ...): desired %s = %v, want none", tc.path, field, c.DesiredComposite.Resource.Object[field])
+				}
+			}
+			if tc.wantErr {
+				return
+			}
+
+			if got := c.DesiredComposite.Resource.Object["status"]; !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("PatchStatus(%q, ...): status = %v, want %v", tc.path, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,403 @@
+package template
+
+import (
+	"crypto/sha1" //nolint:gosec // Used for hashing values, not for security
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/xfnlib/pkg/composite"
+)
+
+// FuncMap returns the functions available to templates
+//
+// The functions follow the names and argument order of the sprig library so
+// existing templates keep working. Argument order puts the value being worked
+// on last so functions can be used in pipelines.
+//
+//   - Strings: `upper`, `lower`, `title`, `trim`, `trimAll`, `trimPrefix`,
+//     `trimSuffix`, `replace`, `contains`, `hasPrefix`, `hasSuffix`,
+//     `splitList`, `join`, `quote`, `squote`, `indent`, `nindent`, `trunc`,
+//     `toString`
+//   - Defaults: `default`, `empty`, `coalesce`, `ternary`, `required`, `fail`
+//   - Encoding: `toYaml`, `fromYaml`, `toJson`, `fromJson`, `b64enc`,
+//     `b64dec`, `sha1sum`, `sha256sum`
+//   - Collections: `list`, `dict`, `get`, `hasKey`, `keys`, `dig`
+//   - Numbers: `int`, `int64`, `float64`, `add`, `sub`, `mul`, `div`, `mod`,
+//     `max`, `min`
+//   - Names: `resourceName`, `subdomainName`
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      title,
+		"trim":       strings.TrimSpace,
+		"trimAll":    func(cutset, s string) string { return strings.Trim(s, cutset) },
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"splitList":  func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":       join,
+		"quote":      func(v any) string { return strconv.Quote(toString(v)) },
+		"squote":     func(v any) string { return "'" + toString(v) + "'" },
+		"indent":     indent,
+		"nindent":    func(n int, s string) string { return "\n" + indent(n, s) },
+		"trunc":      trunc,
+		"toString":   toString,
+
+		"default":  defaultValue,
+		"empty":    empty,
+		"coalesce": coalesce,
+		"ternary":  ternary,
+		"required": required,
+		"fail":     func(msg string) (string, error) { return "", errors.New(msg) },
+
+		"toYaml":    toYaml,
+		"fromYaml":  fromYaml,
+		"toJson":    toJSON,
+		"fromJson":  fromJSON,
+		"b64enc":    func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"b64dec":    b64dec,
+		"sha1sum":   func(s string) string { sum := sha1.Sum([]byte(s)); return hex.EncodeToString(sum[:]) }, //nolint:gosec // Used for hashing values, not for security
+		"sha256sum": func(s string) string { sum := sha256.Sum256([]byte(s)); return hex.EncodeToString(sum[:]) },
+
+		"list":   func(v ...any) []any { return v },
+		"dict":   dict,
+		"get":    get,
+		"hasKey": hasKey,
+		"keys":   keys,
+		"dig":    dig,
+
+		"int":     func(v any) int { return int(toInt64(v)) },
+		"int64":   toInt64,
+		"float64": toFloat64,
+		"add":     func(a ...any) int64 { return fold(a, func(x, y int64) int64 { return x + y }) },
+		"sub":     func(a, b any) int64 { return toInt64(a) - toInt64(b) },
+		"mul":     func(a ...any) int64 { return fold(a, func(x, y int64) int64 { return x * y }) },
+		"div":     div,
+		"mod":     mod,
+		"max":     func(a ...any) int64 { return fold(a, func(x, y int64) int64 { return max(x, y) }) },
+		"min":     func(a ...any) int64 { return fold(a, func(x, y int64) int64 { return min(x, y) }) },
+
+		"resourceName":  composite.ResourceName,
+		"subdomainName": composite.SubdomainName,
+	}
+}
+
+// title upper cases the first letter of every word in s
+func title(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if i == 0 || unicode.IsSpace(runes[i-1]) || runes[i-1] == '-' || runes[i-1] == '_' {
+			runes[i] = unicode.ToTitle(r)
+		}
+	}
+	return string(runes)
+}
+
+// join joins the items of list with sep
+func join(sep string, list any) string {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return toString(list)
+	}
+
+	items := make([]string, v.Len())
+	for i := range items {
+		items[i] = toString(v.Index(i).Interface())
+	}
+	return strings.Join(items, sep)
+}
+
+// indent prefixes every line of s with n spaces
+func indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+// trunc shortens s to n characters. A negative n keeps the last characters
+func trunc(n int, s string) string {
+	switch {
+	case n >= 0 && len(s) > n:
+		return s[:n]
+	case n < 0 && len(s) > -n:
+		return s[len(s)+n:]
+	}
+	return s
+}
+
+// toString formats v as a string
+//
+// Whole numbers are formatted without a fraction or exponent.
+func toString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case error:
+		return t.Error()
+	case fmt.Stringer:
+		return t.String()
+	}
+	return fmt.Sprint(v)
+}
+
+// defaultValue returns given unless it is empty, otherwise def
+func defaultValue(def any, given ...any) any {
+	if len(given) == 0 || empty(given[0]) {
+		return def
+	}
+	return given[0]
+}
+
+// empty returns true if v is nil or the zero value of its type
+func empty(v any) bool {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return true
+	}
+
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	}
+	return rv.IsZero()
+}
+
+// coalesce returns the first value that is not empty
+func coalesce(v ...any) any {
+	for _, e := range v {
+		if !empty(e) {
+			return e
+		}
+	}
+	return nil
+}
+
+// ternary returns a if cond is true, otherwise b
+func ternary(a, b any, cond bool) any {
+	if cond {
+		return a
+	}
+	return b
+}
+
+// required fails the template with msg if v is empty
+func required(msg string, v any) (any, error) {
+	if empty(v) {
+		return nil, errors.New(msg)
+	}
+	return v, nil
+}
+
+// toYaml encodes v as YAML without a trailing newline
+func toYaml(v any) (string, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot encode yaml")
+	}
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+// fromYaml decodes a YAML document
+func fromYaml(s string) (v any, err error) {
+	if err = yaml.Unmarshal([]byte(s), &v); err != nil {
+		err = errors.Wrap(err, "cannot decode yaml")
+	}
+	return
+}
+
+// toJSON encodes v as JSON
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot encode json")
+	}
+	return string(b), nil
+}
+
+// fromJSON decodes a JSON document
+func fromJSON(s string) (v any, err error) {
+	if err = json.Unmarshal([]byte(s), &v); err != nil {
+		err = errors.Wrap(err, "cannot decode json")
+	}
+	return
+}
+
+// b64dec decodes a base64 encoded string
+func b64dec(s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot decode base64")
+	}
+	return string(b), nil
+}
+
+// dict builds a map from alternating keys and values
+func dict(v ...any) (map[string]any, error) {
+	if len(v)%2 != 0 {
+		return nil, errors.New("dict requires an even number of arguments")
+	}
+
+	d := make(map[string]any, len(v)/2)
+	for i := 0; i < len(v); i += 2 {
+		d[toString(v[i])] = v[i+1]
+	}
+	return d, nil
+}
+
+// get returns the value of key in d or an empty string
+func get(d map[string]any, key string) any {
+	if v, ok := d[key]; ok {
+		return v
+	}
+	return ""
+}
+
+// hasKey returns true if d holds key
+func hasKey(d map[string]any, key string) bool {
+	_, ok := d[key]
+	return ok
+}
+
+// keys returns the sorted keys of the given maps
+func keys(dicts ...map[string]any) []string {
+	var k []string
+	for _, d := range dicts {
+		for key := range d {
+			k = append(k, key)
+		}
+	}
+	slices.Sort(k)
+	return slices.Compact(k)
+}
+
+// dig walks nested maps along the given keys
+//
+// The arguments are the keys, a default returned when a key is missing, and
+// the map to walk.
+func dig(args ...any) (any, error) {
+	if len(args) < 3 {
+		return nil, errors.New("dig requires at least one key, a default and a map")
+	}
+
+	def := args[len(args)-2]
+	current, ok := args[len(args)-1].(map[string]any)
+	if !ok {
+		return nil, errors.Errorf("dig cannot walk %T", args[len(args)-1])
+	}
+
+	path := args[:len(args)-2]
+	for i, k := range path {
+		v, ok := current[toString(k)]
+		if !ok {
+			return def, nil
+		}
+
+		if i == len(path)-1 {
+			return v, nil
+		}
+
+		if current, ok = v.(map[string]any); !ok {
+			return def, nil
+		}
+	}
+	return def, nil
+}
+
+// toInt64 converts v to an int64. Values that cannot be converted return 0
+func toInt64(v any) int64 {
+	switch t := v.(type) {
+	case string:
+		if i, err := strconv.ParseInt(t, 10, 64); err == nil {
+			return i
+		}
+		return int64(toFloat64(t))
+	case bool:
+		if t {
+			return 1
+		}
+		return 0
+	}
+	return int64(toFloat64(v))
+}
+
+// toFloat64 converts v to a float64. Values that cannot be converted return 0
+func toFloat64(v any) float64 {
+	switch t := v.(type) {
+	case string:
+		f, _ := strconv.ParseFloat(t, 64)
+		return f
+	case json.Number:
+		f, _ := t.Float64()
+		return f
+	case bool:
+		if t {
+			return 1
+		}
+		return 0
+	}
+
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.CanInt():
+		return float64(rv.Int())
+	case rv.CanUint():
+		return float64(rv.Uint())
+	case rv.CanFloat():
+		return rv.Float()
+	}
+	return 0
+}
+
+// fold combines the integer values of a with fn
+func fold(a []any, fn func(x, y int64) int64) int64 {
+	if len(a) == 0 {
+		return 0
+	}
+
+	out := toInt64(a[0])
+	for _, v := range a[1:] {
+		out = fn(out, toInt64(v))
+	}
+	return out
+}
+
+// div divides a by b
+func div(a, b any) (int64, error) {
+	d := toInt64(b)
+	if d == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return toInt64(a) / d, nil
+}
+
+// mod returns the remainder of dividing a by b
+func mod(a, b any) (int64, error) {
+	d := toInt64(b)
+	if d == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return toInt64(a) % d, nil
+}
@@ -0,0 +1,85 @@
+package template
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+)
+
+func TestFuncMap(t *testing.T) {
+	data := map[string]any{
+		"empty":  "",
+		"name":   "cluster",
+		"list":   []any{"a", "b"},
+		"nested": map[string]any{"a": map[string]any{"b": "c"}, "d": "e"},
+		"number": float64(3),
+	}
+
+	cases := map[string]struct {
+		text    string
+		want    string
+		wantErr bool
+	}{
+		"Upper":        {text: `{{ .name | upper }}`, want: "CLUSTER"},
+		"Title":        {text: `{{ "my-cluster name" | title }}`, want: "My-Cluster Name"},
+		"TrimPrefix":   {text: `{{ .name | trimPrefix "clu" }}`, want: "ster"},
+		"Replace":      {text: `{{ .name | replace "u" "o" }}`, want: "closter"},
+		"Contains":     {text: `{{ .name | contains "lus" }}`, want: "true"},
+		"SplitJoin":    {text: `{{ "a,b,c" | splitList "," | join "-" }}`, want: "a-b-c"},
+		"Quote":        {text: `{{ .number | quote }}`, want: `"3"`},
+		"Indent":       {text: `{{ "a\nb" | indent 2 }}`, want: "  a\n  b"},
+		"Nindent":      {text: `{{ "a" | nindent 2 }}`, want: "\n  a"},
+		"Trunc":        {text: `{{ .name | trunc 3 }}`, want: "clu"},
+		"TruncEnd":     {text: `{{ .name | trunc -3 }}`, want: "ter"},
+		"Default":      {text: `{{ .empty | default "d" }}`, want: "d"},
+		"DefaultSet":   {text: `{{ .name | default "d" }}`, want: "cluster"},
+		"DefaultUnset": {text: `{{ .missing | default "d" }}`, want: "d"},
+		"Coalesce":     {text: `{{ coalesce .empty .missing .name }}`, want: "cluster"},
+		"Ternary":      {text: `{{ ternary "a" "b" (empty .empty) }}`, want: "a"},
+		"Required":     {text: `{{ required "name is required" .missing }}`, wantErr: true},
+		"Fail":         {text: `{{ fail "boom" }}`, wantErr: true},
+		"ToYaml":       {text: `{{ dict "b" 1 "a" .list | toYaml }}`, want: "a:\n- a\n- b\nb: 1"},
+		"FromYaml":     {text: `{{ (fromYaml "a: {b: c}").a.b }}`, want: "c"},
+		"ToJson":       {text: `{{ .nested | toJson }}`, want: `{"a":{"b":"c"},"d":"e"}`},
+		"FromJson":     {text: `{{ (fromJson "{\"a\": 1}").a }}`, want: "1"},
+		"Base64":       {text: `{{ .name | b64enc | b64dec }}`, want: "cluster"},
+		"InvalidB64":   {text: `{{ b64dec "%" }}`, wantErr: true},
+		"Sha256sum":    {text: `{{ "a" | sha256sum }}`, want: "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb"},
+		"Get":          {text: `{{ get .nested "d" }}`, want: "e"},
+		"HasKey":       {text: `{{ hasKey .nested "x" }}`, want: "false"},
+		"Keys":         {text: `{{ keys .nested | join "," }}`, want: "a,d"},
+		"Dig":          {text: `{{ dig "a" "b" "none" .nested }}`, want: "c"},
+		"DigDefault":   {text: `{{ dig "a" "x" "none" .nested }}`, want: "none"},
+		"DictOdd":      {text: `{{ dict "a" }}`, wantErr: true},
+		"Arithmetic":   {text: `{{ add 1 .number 2 }} {{ sub 5 .number }} {{ mul 2 3 }} {{ div 7 2 }} {{ mod 7 2 }}`, want: "6 2 6 3 1"},
+		"MaxMin":       {text: `{{ max 1 .number 2 }} {{ min 4 .number }}`, want: "3 3"},
+		"DivideByZero": {text: `{{ div 1 0 }}`, wantErr: true},
+		"Int":          {text: `{{ int "42" }}`, want: "42"},
+		"ResourceName": {text: `{{ resourceName .name "db" }}`, want: "cluster-db"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := template.New(name).Funcs(FuncMap()).Parse(tc.text)
+			if err != nil {
+				t.Fatalf("Parse(%q): unexpected error: %v", tc.text, err)
+			}
+
+			var b bytes.Buffer
+			err = tmpl.Execute(&b, data)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Execute(%q) = %q, want error", tc.text, b.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute(%q): unexpected error: %v", tc.text, err)
+			}
+
+			if got := b.String(); got != tc.want {
+				t.Errorf("Execute(%q) = %q, want %q", tc.text, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+// Package template renders composed resources from Go templates.
+//
+// Templates produce multi-document YAML. Every document is a composed resource
+// and must carry the `AnnotationResourceName` annotation holding its pipeline
+// name. The annotation is removed before the resource is added to the
+// composition with `AddDesired`. Documents are read with
+// `composite.ParseManifests`, so `apiVersion` and `kind` must be set while
+// `metadata.name` may be left for Crossplane to generate.
+//
+// Templates are executed with a `Data` value. The observed composite resource
+// is available as `.Observed.Composite`, the observed composed resources as
+// `.Observed.Resources`, the pipeline context as `.Context` and the function
+// input as `.Input`.
+//
+// Example:
+//
+//	//go:embed templates
+//	var templates embed.FS
+//
+//	t, err := template.ParseFS(templates, "templates/*.yaml")
+//	if err != nil {
+//		return
+//	}
+//	err = template.Render(composed, t)
+package template
+
+import (
+	"bytes"
+	"io/fs"
+	"path"
+	"slices"
+	"text/template"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/giantswarm/xfnlib/pkg/composite"
+)
+
+// AnnotationResourceName is the annotation holding the pipeline name of a
+// rendered resource
+const AnnotationResourceName = "xfnlib.giantswarm.io/composition-resource-name"
+
+// Source is a template carried in the function Input
+//
+// Embed a list of these in the Input to let the composition supply the
+// templates.
+type Source struct {
+	// Name of the template. Used to refer to the template from other templates
+	// and in error messages
+	Name string `json:"name"`
+
+	// Inline is the template text
+	Inline string `json:"inline"`
+}
+
+// Data is the value templates are executed with
+type Data struct {
+	// Observed holds the observed state of the composition
+	Observed ObservedData
+
+	// Context is the pipeline context
+	Context map[string]any
+
+	// Input is the function input
+	Input map[string]any
+}
+
+// ObservedData holds the observed resources of the composition
+type ObservedData struct {
+	// Composite is the observed composite resource
+	Composite map[string]any
+
+	// Resources holds the observed composed resources by pipeline name
+	Resources map[string]any
+}
+
+// Template is a set of parsed templates
+//
+// Every template is rendered in name order. Templates only holding `define`
+// blocks render to nothing and can be used for helpers.
+type Template struct {
+	tmpl  *template.Template
+	names []string
+}
+
+// Parse parses a single template
+func Parse(name, text string) (t *Template, err error) {
+	return ParseSource(Source{Name: name, Inline: text})
+}
+
+// ParseSource parses templates supplied through the function Input
+//
+// A source repeating the name of an earlier one replaces it.
+func ParseSource(sources ...Source) (t *Template, err error) {
+	t = newTemplate()
+	for _, s := range sources {
+		if _, err = t.tmpl.New(s.Name).Parse(s.Inline); err != nil {
+			err = errors.Wrapf(err, "cannot parse template %q", s.Name)
+			return
+		}
+		t.names = append(t.names, s.Name)
+	}
+	slices.Sort(t.names)
+	t.names = slices.Compact(t.names)
+	return
+}
+
+// ParseFS parses the files matching patterns in fsys, for example an
+// `embed.FS`
+//
+// Templates are named after the base name of their file. Files in different
+// directories sharing a base name are rejected, as one would silently replace
+// the other.
+func ParseFS(fsys fs.FS, patterns ...string) (t *Template, err error) {
+	t = newTemplate()
+	files := make(map[string]string)
+	for _, p := range patterns {
+		var matches []string
+		if matches, err = fs.Glob(fsys, p); err != nil {
+			err = errors.Wrapf(err, "cannot match %q", p)
+			return
+		}
+
+		if len(matches) == 0 {
+			err = errors.Errorf("pattern %q matches no files", p)
+			return
+		}
+
+		for _, f := range matches {
+			name := path.Base(f)
+			if other, ok := files[name]; ok {
+				if other == f {
+					continue
+				}
+				err = errors.Errorf("templates %q and %q share the name %q", other, f, name)
+				return
+			}
+			files[name] = f
+
+			var b []byte
+			if b, err = fs.ReadFile(fsys, f); err != nil {
+				err = errors.Wrapf(err, "cannot read template %q", f)
+				return
+			}
+
+			if _, err = t.tmpl.New(name).Parse(string(b)); err != nil {
+				err = errors.Wrapf(err, "cannot parse template %q", f)
+				return
+			}
+			t.names = append(t.names, name)
+		}
+	}
+	slices.Sort(t.names)
+	return
+}
+
+// newTemplate creates an empty template set with the template functions and
+// `include` registered
+func newTemplate() *Template {
+	t := &Template{}
+	t.tmpl = template.New("").Funcs(FuncMap()).Funcs(template.FuncMap{
+		"include": t.include,
+	})
+	return t
+}
+
+// include renders the named template to a string so it can be piped into
+// other functions
+func (t *Template) include(name string, data any) (string, error) {
+	var b bytes.Buffer
+	if err := t.tmpl.ExecuteTemplate(&b, name, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Execute renders every template with data and returns the output of each
+// template by name
+func (t *Template) Execute(data Data) (out map[string][]byte, err error) {
+	out = make(map[string][]byte, len(t.names))
+	for _, n := range t.names {
+		var b bytes.Buffer
+		if err = t.tmpl.ExecuteTemplate(&b, n, data); err != nil {
+			err = errors.Wrapf(err, "cannot execute template %q", n)
+			return
+		}
+		out[n] = b.Bytes()
+	}
+	return
+}
+
+// NewData builds the template data from the state of a composition
+func NewData[XR any, In composite.InputProvider](c *composite.TypedComposition[XR, In]) (d Data, err error) {
+	d = Data{
+		Observed: ObservedData{
+			Composite: c.ObservedCompositeObject(),
+			Resources: make(map[string]any, len(c.ObservedComposed)),
+		},
+		Context: c.Context(),
+	}
+
+	for n, o := range c.ObservedComposed {
+		var object map[string]any
+		if err = composite.To(o.Resource.Object, &object); err != nil {
+			err = errors.Wrapf(err, "cannot convert observed resource %q", n)
+			return
+		}
+		d.Observed.Resources[string(n)] = object
+	}
+
+	if err = composite.To(c.Input, &d.Input); err != nil {
+		err = errors.Wrap(err, "cannot convert input")
+	}
+	return
+}
+
+// Render executes t against the composition and adds every rendered document
+// with `AddDesired`
+//
+//   - `c` The composition to read the template data from and to add the
+//     resources to
+//   - `t` The templates to render
+//   - `opts` Options passed to `AddDesired` for every resource
+func Render[XR any, In composite.InputProvider](c *composite.TypedComposition[XR, In], t *Template, opts ...composite.DesiredOption) (err error) {
+	var data Data
+	if data, err = NewData(c); err != nil {
+		return
+	}
+
+	var out map[string][]byte
+	if out, err = t.Execute(data); err != nil {
+		return
+	}
+
+	for _, n := range t.names {
+		var manifests []composite.Manifest
+		if manifests, err = composite.ParseManifests(out[n],
+			composite.WithManifestSource(n),
+			composite.WithNameAnnotation(AnnotationResourceName),
+			composite.WithGeneratedNames(),
+		); err != nil {
+			return
+		}
+
+		if err = c.AddManifests(manifests, opts...); err != nil {
+			return
+		}
+	}
+	return
+}
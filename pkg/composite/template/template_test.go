@@ -0,0 +1,235 @@
+package template
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"github.com/crossplane/function-sdk-go/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/giantswarm/xfnlib/pkg/composite"
+)
+
+// testComposition builds a composition observing an XR in eu-west-1
+func testComposition(t *testing.T) *composite.TypedComposition[map[string]any, *unstructured.Unstructured] {
+	t.Helper()
+
+	req := &fnv1.RunFunctionRequest{
+		Observed: &fnv1.State{
+			Composite: &fnv1.Resource{Resource: resource.MustStructJSON(`{
+				"apiVersion": "test.xfnlib.io/v1",
+				"kind": "XTest",
+				"metadata": {"name": "xr"},
+				"spec": {"region": "eu-west-1"}
+			}`)},
+		},
+		Desired: &fnv1.State{
+			Composite: &fnv1.Resource{Resource: resource.MustStructJSON(`{}`)},
+		},
+		Input: resource.MustStructJSON(`{"apiVersion": "test.xfnlib.io/v1", "kind": "Input", "spec": {"owner": "team"}}`),
+	}
+
+	c, err := composite.NewTyped[map[string]any](req, &unstructured.Unstructured{})
+	if err != nil {
+		t.Fatalf("NewTyped(...): unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestRender(t *testing.T) {
+	configMap := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  annotations:
+    xfnlib.giantswarm.io/composition-resource-name: {{ .name }}
+data:
+  region: {{ .region }}
+`
+
+	cases := map[string]struct {
+		sources []Source
+		want    map[string]map[string]any
+		wantErr bool
+	}{
+		"ReadsData": {
+			sources: []Source{{Name: "cm.yaml", Inline: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  annotations:
+    xfnlib.giantswarm.io/composition-resource-name: cm
+data:
+  region: {{ .Observed.Composite.spec.region }}
+  owner: {{ .Input.spec.owner }}
+`}},
+			want: map[string]map[string]any{
+				"cm": {"region": "eu-west-1", "owner": "team"},
+			},
+		},
+		"HelpersAndDocuments": {
+			sources: []Source{
+				{Name: "_helpers.tpl", Inline: `{{ define "cm" }}` + configMap + `{{ end }}`},
+				{Name: "cms.yaml", Inline: `{{ include "cm" (dict "name" "a" "region" "a-1") }}
+---
+{{ include "cm" (dict "name" "b" "region" "b-1") }}`},
+			},
+			want: map[string]map[string]any{
+				"a": {"region": "a-1"},
+				"b": {"region": "b-1"},
+			},
+		},
+		"RepeatedSourceReplaces": {
+			sources: []Source{
+				{Name: "cm.yaml", Inline: `{{ template "x" }}`},
+				{Name: "cm.yaml", Inline: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  annotations:
+    xfnlib.giantswarm.io/composition-resource-name: cm
+data:
+  region: b
+`},
+			},
+			want: map[string]map[string]any{
+				"cm": {"region": "b"},
+			},
+		},
+		"MissingAnnotation": {
+			sources: []Source{{Name: "cm.yaml", Inline: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n"}},
+			wantErr: true,
+		},
+		"ExecuteError": {
+			sources: []Source{{Name: "cm.yaml", Inline: `{{ required "owner is required" .Input.spec.missing }}`}},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := ParseSource(tc.sources...)
+			if err != nil {
+				t.Fatalf("ParseSource(...): unexpected error: %v", err)
+			}
+
+			c := testComposition(t)
+			err = Render(c, tmpl)
+			if tc.wantErr {
+				if err == nil {
+					t.Error("Render(...): want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render(...): unexpected error: %v", err)
+			}
+
+			got := make(map[string]map[string]any, len(c.DesiredComposed))
+			for n, d := range c.DesiredComposed {
+				if a := d.Resource.GetAnnotations(); a[AnnotationResourceName] != "" {
+					t.Errorf("Render(...): %q keeps annotation %q", n, AnnotationResourceName)
+				}
+				data, _ := d.Resource.Object["data"].(map[string]any)
+				got[string(n)] = data
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Render(...): desired data = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	cases := map[string]struct {
+		sources   []Source
+		wantNames []string
+		wantErr   bool
+	}{
+		"Sorted": {
+			sources:   []Source{{Name: "b", Inline: "b"}, {Name: "a", Inline: "a"}},
+			wantNames: []string{"a", "b"},
+		},
+		"Repeated": {
+			sources:   []Source{{Name: "a", Inline: "1"}, {Name: "b", Inline: "b"}, {Name: "a", Inline: "2"}},
+			wantNames: []string{"a", "b"},
+		},
+		"Invalid": {
+			sources: []Source{{Name: "a", Inline: "{{ if }}"}},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := ParseSource(tc.sources...)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseSource(...): error = %v, want error %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(tmpl.names, tc.wantNames) {
+				t.Errorf("ParseSource(...): names = %v, want %v", tmpl.names, tc.wantNames)
+			}
+		})
+	}
+}
+
+func TestParseFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/a.yaml":   {Data: []byte("a")},
+		"templates/b.yaml":   {Data: []byte("b")},
+		"templates/bad.tpl":  {Data: []byte("{{ if }}")},
+		"overrides/a.yaml":   {Data: []byte("other a")},
+		"overrides/c.yaml":   {Data: []byte("c")},
+		"templates/_helpers": {Data: []byte(`{{ define "x" }}x{{ end }}`)},
+	}
+
+	cases := map[string]struct {
+		patterns  []string
+		wantNames []string
+		wantErr   bool
+	}{
+		"Files": {
+			patterns:  []string{"templates/*.yaml", "templates/_helpers"},
+			wantNames: []string{"_helpers", "a.yaml", "b.yaml"},
+		},
+		"OverlappingPatterns": {
+			patterns:  []string{"templates/*.yaml", "templates/a.*"},
+			wantNames: []string{"a.yaml", "b.yaml"},
+		},
+		"DifferentDirectories": {
+			patterns:  []string{"templates/b.yaml", "overrides/c.yaml"},
+			wantNames: []string{"b.yaml", "c.yaml"},
+		},
+		"SameBaseName": {
+			patterns: []string{"templates/*.yaml", "overrides/*.yaml"},
+			wantErr:  true,
+		},
+		"NoMatch": {
+			patterns: []string{"missing/*.yaml"},
+			wantErr:  true,
+		},
+		"Invalid": {
+			patterns: []string{"templates/*.tpl"},
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := ParseFS(fsys, tc.patterns...)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseFS(...): error = %v, want error %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(tmpl.names, tc.wantNames) {
+				t.Errorf("ParseFS(...): names = %v, want %v", tmpl.names, tc.wantNames)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ConvertType is the type a convert transform converts to
+type ConvertType string
+
+// Supported convert target types
+const (
+	ConvertTypeString  ConvertType = "string"
+	ConvertTypeInt     ConvertType = "int"
+	ConvertTypeInt64   ConvertType = "int64"
+	ConvertTypeFloat64 ConvertType = "float64"
+	ConvertTypeBool    ConvertType = "bool"
+	ConvertTypeObject  ConvertType = "object"
+	ConvertTypeArray   ConvertType = "array"
+)
+
+// ConvertFormat is the format of the input value of a convert transform
+type ConvertFormat string
+
+const (
+	// ConvertFormatNone converts the value as is. This is the default
+	ConvertFormatNone ConvertFormat = "none"
+
+	// ConvertFormatQuantity parses a string as a kubernetes quantity, for
+	// example `500m` or `2Gi`. Only valid when converting to a number
+	ConvertFormatQuantity ConvertFormat = "quantity"
+
+	// ConvertFormatJSON parses a string as JSON. Only valid when converting to
+	// an object or an array
+	ConvertFormatJSON ConvertFormat = "json"
+)
+
+// ConvertTransform converts the value to another type
+type ConvertTransform struct {
+	// ToType is the type to convert to
+	ToType ConvertType `json:"toType"`
+
+	// Format of the input value. Defaults to none
+	Format *ConvertFormat `json:"format,omitempty"`
+}
+
+// Convert builds a transform converting the value to the type to
+func Convert(to ConvertType) Transform {
+	return Transform{
+		Type: TypeConvert,
+		Convert: &ConvertTransform{
+			ToType: to,
+		},
+	}
+}
+
+// ConvertWithFormat builds a transform parsing the value in format and
+// converting it to the type to
+//
+// Example:
+//
+//	// "500m" -> 0.5
+//	t := transform.ConvertWithFormat(transform.ConvertTypeFloat64, transform.ConvertFormatQuantity)
+func ConvertWithFormat(to ConvertType, format ConvertFormat) Transform {
+	return Transform{
+		Type: TypeConvert,
+		Convert: &ConvertTransform{
+			ToType: to,
+			Format: &format,
+		},
+	}
+}
+
+// resolve converts v
+func (c *ConvertTransform) resolve(v any) (out any, err error) {
+	format := ConvertFormatNone
+	if c.Format != nil && *c.Format != "" {
+		format = *c.Format
+	}
+
+	switch format {
+	case ConvertFormatNone:
+	case ConvertFormatQuantity:
+		if v, err = parseQuantity(v, c.ToType); err != nil {
+			return
+		}
+	case ConvertFormatJSON:
+		if v, err = parseJSON(v, c.ToType); err != nil {
+			return
+		}
+	default:
+		err = errors.Wrapf(ErrInvalidTransform, "unknown convert format %q", format)
+		return
+	}
+
+	switch c.ToType {
+	case ConvertTypeString:
+		return toString(v)
+	case ConvertTypeInt, ConvertTypeInt64:
+		return toInt(v)
+	case ConvertTypeFloat64:
+		return toFloat64(v)
+	case ConvertTypeBool:
+		return toBool(v)
+	case ConvertTypeObject:
+		if o, ok := v.(map[string]any); ok {
+			return o, nil
+		}
+	case ConvertTypeArray:
+		if a, ok := v.([]any); ok {
+			return a, nil
+		}
+	default:
+		err = errors.Wrapf(ErrInvalidTransform, "unknown convert type %q", c.ToType)
+		return
+	}
+
+	err = errors.Wrapf(ErrInvalidInput, "cannot convert %T to %s", v, c.ToType)
+	return
+}
+
+// parseQuantity parses v as a kubernetes quantity
+func parseQuantity(v any, to ConvertType) (out any, err error) {
+	if to != ConvertTypeInt && to != ConvertTypeInt64 && to != ConvertTypeFloat64 {
+		err = errors.Wrapf(ErrInvalidTransform, "quantities cannot be converted to %s", to)
+		return
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		err = errors.Wrapf(ErrInvalidInput, "%T is not a quantity", v)
+		return
+	}
+
+	var q resource.Quantity
+	if q, err = resource.ParseQuantity(s); err != nil {
+		err = errors.Wrapf(ErrInvalidInput, "cannot parse quantity %q: %s", s, err)
+		return
+	}
+	out = q.AsApproximateFloat64()
+	return
+}
+
+// parseJSON parses v as a JSON document
+func parseJSON(v any, to ConvertType) (out any, err error) {
+	if to != ConvertTypeObject && to != ConvertTypeArray {
+		err = errors.Wrapf(ErrInvalidTransform, "json cannot be converted to %s", to)
+		return
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		err = errors.Wrapf(ErrInvalidInput, "%T is not a json string", v)
+		return
+	}
+
+	if err = json.Unmarshal([]byte(s), &out); err != nil {
+		err = errors.Wrapf(ErrInvalidInput, "cannot parse json: %s", err)
+	}
+	return
+}
+
+// toString converts a scalar to its string representation
+func toString(v any) (s string, err error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	case float32:
+		return strconv.FormatFloat(float64(t), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, json.Number:
+		return fmt.Sprint(t), nil
+	}
+
+	err = errors.Wrapf(ErrInvalidInput, "%T is not a scalar", v)
+	return
+}
+
+// toFloat converts a number to a float64
+//
+// integer is true if v is of an integer type.
+func toFloat(v any) (f float64, integer bool, ok bool) {
+	switch t := v.(type) {
+	case int:
+		return float64(t), true, true
+	case int8:
+		return float64(t), true, true
+	case int16:
+		return float64(t), true, true
+	case int32:
+		return float64(t), true, true
+	case int64:
+		return float64(t), true, true
+	case uint:
+		return float64(t), true, true
+	case uint8:
+		return float64(t), true, true
+	case uint16:
+		return float64(t), true, true
+	case uint32:
+		return float64(t), true, true
+	case uint64:
+		return float64(t), true, true
+	case float32:
+		return float64(t), false, true
+	case float64:
+		return t, false, true
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return float64(i), true, true
+		}
+		if f, err := t.Float64(); err == nil {
+			return f, false, true
+		}
+	}
+	return 0, false, false
+}
+
+// toInt converts v to an int64
+//
+// Floats must be whole numbers and strings must hold an integer.
+func toInt(v any) (i int64, err error) {
+	switch t := v.(type) {
+	case string:
+		if i, err = strconv.ParseInt(t, 10, 64); err != nil {
+			err = errors.Wrapf(ErrInvalidInput, "cannot parse %q as an integer", t)
+		}
+		return
+	case bool:
+		if t {
+			i = 1
+		}
+		return
+	}
+
+	f, _, ok := toFloat(v)
+	if !ok {
+		err = errors.Wrapf(ErrInvalidInput, "cannot convert %T to an integer", v)
+		return
+	}
+
+	if f != math.Trunc(f) {
+		err = errors.Wrapf(ErrInvalidInput, "%v is not a whole number", f)
+		return
+	}
+	i = int64(f)
+	return
+}
+
+// toFloat64 converts v to a float64
+func toFloat64(v any) (f float64, err error) {
+	switch t := v.(type) {
+	case string:
+		if f, err = strconv.ParseFloat(t, 64); err != nil {
+			err = errors.Wrapf(ErrInvalidInput, "cannot parse %q as a number", t)
+		}
+		return
+	case bool:
+		if t {
+			f = 1
+		}
+		return
+	}
+
+	var ok bool
+	if f, _, ok = toFloat(v); !ok {
+		err = errors.Wrapf(ErrInvalidInput, "cannot convert %T to a number", v)
+	}
+	return
+}
+
+// toBool converts v to a bool
+//
+// Strings are parsed with `strconv.ParseBool` and numbers are true unless zero.
+func toBool(v any) (b bool, err error) {
+	switch t := v.(type) {
+	case bool:
+		return t, nil
+	case string:
+		if b, err = strconv.ParseBool(t); err != nil {
+			err = errors.Wrapf(ErrInvalidInput, "cannot parse %q as a bool", t)
+		}
+		return
+	}
+
+	f, _, ok := toFloat(v)
+	if !ok {
+		err = errors.Wrapf(ErrInvalidInput, "cannot convert %T to a bool", v)
+		return
+	}
+	b = f != 0
+	return
+}
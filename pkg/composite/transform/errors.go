@@ -0,0 +1,47 @@
+package transform
+
+import (
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Errors returned by transforms. Use `errors.Is` to test for them.
+var (
+	// ErrInvalidTransform is returned when a transform is not configured
+	// correctly
+	ErrInvalidTransform = errors.New("invalid transform")
+
+	// ErrInvalidInput is returned when the value cannot be handled by the
+	// transform
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrNotFound is returned when a map transform does not contain the value
+	ErrNotFound = errors.New("key not found")
+
+	// ErrNoMatch is returned when no pattern of a match transform matches the
+	// value and no fallback is configured
+	ErrNoMatch = errors.New("no pattern matched")
+)
+
+// Error is returned by `Apply` when a transform fails
+//
+// +kubebuilder:object:generate=false
+type Error struct {
+	// Index of the failing transform
+	Index int
+
+	// Type of the failing transform
+	Type Type
+
+	// Err is the error returned by the transform
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s transform at index %d failed: %s", e.Type, e.Index, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,147 @@
+package transform
+
+import (
+	"regexp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// MatchType is the type of a match pattern
+type MatchType string
+
+// Supported match pattern types
+const (
+	MatchTypeLiteral MatchType = "literal"
+	MatchTypeRegexp  MatchType = "regexp"
+)
+
+// MatchFallbackTo decides what a match transform returns when no pattern
+// matches
+type MatchFallbackTo string
+
+const (
+	// MatchFallbackToValue returns FallbackValue. This is the default
+	MatchFallbackToValue MatchFallbackTo = "Value"
+
+	// MatchFallbackToInput returns the input unchanged
+	MatchFallbackToInput MatchFallbackTo = "Input"
+)
+
+// MatchTransform returns the result of the first pattern matching the value
+type MatchTransform struct {
+	// Patterns are evaluated in order
+	Patterns []MatchPattern `json:"patterns"`
+
+	// FallbackValue is returned when no pattern matches. Without a fallback
+	// an `ErrNoMatch` error is returned
+	// +optional
+	FallbackValue *extv1.JSON `json:"fallbackValue,omitempty"`
+
+	// FallbackTo decides whether FallbackValue or the input is returned when
+	// no pattern matches
+	FallbackTo MatchFallbackTo `json:"fallbackTo,omitempty"`
+}
+
+// MatchPattern is a single pattern of a match transform
+type MatchPattern struct {
+	// Type of the pattern
+	Type MatchType `json:"type"`
+
+	// Literal the value must equal. Required for literal patterns
+	Literal *string `json:"literal,omitempty"`
+
+	// Regexp the value must match. Required for regexp patterns
+	Regexp *string `json:"regexp,omitempty"`
+
+	// Result is returned when the pattern matches
+	Result extv1.JSON `json:"result"`
+}
+
+// Match builds a transform returning the result of the first matching pattern
+func Match(patterns ...MatchPattern) Transform {
+	return Transform{
+		Type: TypeMatch,
+		Match: &MatchTransform{
+			Patterns: patterns,
+		},
+	}
+}
+
+// MatchWithFallback builds a match transform that returns fallback when no
+// pattern matches
+func MatchWithFallback(fallback any, patterns ...MatchPattern) Transform {
+	t := Match(patterns...)
+	value := toJSON(fallback)
+	t.Match.FallbackValue = &value
+	return t
+}
+
+// MatchLiteral builds a pattern matching values equal to literal
+func MatchLiteral(literal string, result any) MatchPattern {
+	return MatchPattern{
+		Type:    MatchTypeLiteral,
+		Literal: &literal,
+		Result:  toJSON(result),
+	}
+}
+
+// MatchRegexp builds a pattern matching values matching expr
+func MatchRegexp(expr string, result any) MatchPattern {
+	return MatchPattern{
+		Type:   MatchTypeRegexp,
+		Regexp: &expr,
+		Result: toJSON(result),
+	}
+}
+
+// resolve returns the result of the first pattern matching v
+func (m *MatchTransform) resolve(v any) (out any, err error) {
+	var s string
+	if s, err = toString(v); err != nil {
+		return
+	}
+
+	for _, p := range m.Patterns {
+		var matched bool
+		if matched, err = p.matches(s); err != nil {
+			return
+		}
+
+		if matched {
+			return fromJSON(p.Result)
+		}
+	}
+
+	switch {
+	case m.FallbackTo == MatchFallbackToInput:
+		out = v
+	case m.FallbackValue != nil:
+		return fromJSON(*m.FallbackValue)
+	default:
+		err = errors.Wrapf(ErrNoMatch, "value %q", s)
+	}
+	return
+}
+
+// matches returns true if s matches the pattern
+func (p MatchPattern) matches(s string) (bool, error) {
+	switch p.Type {
+	case MatchTypeLiteral:
+		if p.Literal == nil {
+			return false, errors.Wrap(ErrInvalidTransform, "literal pattern has no literal")
+		}
+		return s == *p.Literal, nil
+	case MatchTypeRegexp:
+		if p.Regexp == nil {
+			return false, errors.Wrap(ErrInvalidTransform, "regexp pattern has no regexp")
+		}
+
+		re, err := regexp.Compile(*p.Regexp)
+		if err != nil {
+			return false, errors.Wrapf(ErrInvalidTransform, "cannot compile %q: %s", *p.Regexp, err)
+		}
+		return re.MatchString(s), nil
+	}
+	return false, errors.Wrapf(ErrInvalidTransform, "unknown pattern type %q", p.Type)
+}
@@ -0,0 +1,190 @@
+package transform
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// MathType is the type of a math transform
+type MathType string
+
+// Supported math transform types
+const (
+	MathTypeMultiply MathType = "Multiply"
+	MathTypeClampMin MathType = "ClampMin"
+	MathTypeClampMax MathType = "ClampMax"
+	MathTypeClamp    MathType = "Clamp"
+)
+
+// MathTransform applies a mathematical operation to a number
+//
+// Integer input produces integer output as long as the result is a whole
+// number. Operands are free-form so that an Input holding them gets a CRD
+// schema without floats. They accept numbers such as `2` or `1e-12` as well as
+// strings such as `"0.5"` or `"500m"`. Values and operands that are not finite
+// are rejected.
+type MathTransform struct {
+	// Type of the operation
+	Type MathType `json:"type"`
+
+	// Multiply the value by this factor. Required for Multiply
+	Multiply *extv1.JSON `json:"multiply,omitempty"`
+
+	// ClampMin is the lowest value returned. Required for ClampMin and Clamp
+	ClampMin *extv1.JSON `json:"clampMin,omitempty"`
+
+	// ClampMax is the highest value returned. Required for ClampMax and Clamp
+	ClampMax *extv1.JSON `json:"clampMax,omitempty"`
+}
+
+// Multiply builds a transform multiplying the value by factor
+func Multiply(factor float64) Transform {
+	return Transform{
+		Type: TypeMath,
+		Math: &MathTransform{
+			Type:     MathTypeMultiply,
+			Multiply: number(factor),
+		},
+	}
+}
+
+// ClampMin builds a transform raising the value to at least minimum
+func ClampMin(minimum float64) Transform {
+	return Transform{
+		Type: TypeMath,
+		Math: &MathTransform{
+			Type:     MathTypeClampMin,
+			ClampMin: number(minimum),
+		},
+	}
+}
+
+// ClampMax builds a transform lowering the value to at most maximum
+func ClampMax(maximum float64) Transform {
+	return Transform{
+		Type: TypeMath,
+		Math: &MathTransform{
+			Type:     MathTypeClampMax,
+			ClampMax: number(maximum),
+		},
+	}
+}
+
+// Clamp builds a transform keeping the value between minimum and maximum
+func Clamp(minimum, maximum float64) Transform {
+	return Transform{
+		Type: TypeMath,
+		Math: &MathTransform{
+			Type:     MathTypeClamp,
+			ClampMin: number(minimum),
+			ClampMax: number(maximum),
+		},
+	}
+}
+
+// number holds f as a math operand without losing precision. Values that
+// are not finite are held as strings and rejected when the transform runs
+func number(f float64) *extv1.JSON {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		s = strconv.Quote(s)
+	}
+	return &extv1.JSON{Raw: []byte(s)}
+}
+
+// operand decodes a math operand. Strings are parsed as decimals and then as
+// Kubernetes quantities
+func operand(j *extv1.JSON) (f float64, err error) {
+	var v any
+	if err = json.Unmarshal(j.Raw, &v); err != nil {
+		err = errors.Wrapf(ErrInvalidTransform, "cannot decode operand %s: %s", j.Raw, err)
+		return
+	}
+
+	switch t := v.(type) {
+	case float64:
+		f = t
+	case string:
+		var perr error
+		if f, perr = strconv.ParseFloat(t, 64); perr != nil {
+			q, qerr := resource.ParseQuantity(t)
+			if qerr != nil {
+				err = errors.Wrapf(ErrInvalidTransform, "cannot parse operand %q as a number", t)
+				return
+			}
+			f = q.AsApproximateFloat64()
+		}
+	default:
+		err = errors.Wrapf(ErrInvalidTransform, "operand %s is not a number", j.Raw)
+		return
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		err = errors.Wrapf(ErrInvalidInput, "operand %s is not finite", j.Raw)
+	}
+	return
+}
+
+// resolve applies the operation to v
+func (m *MathTransform) resolve(v any) (out any, err error) {
+	f, integer, ok := toFloat(v)
+	if !ok {
+		err = errors.Wrapf(ErrInvalidInput, "%T is not a number", v)
+		return
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		err = errors.Wrapf(ErrInvalidInput, "%v is not finite", f)
+		return
+	}
+
+	switch m.Type {
+	case MathTypeMultiply:
+		if m.Multiply == nil {
+			err = errors.Wrap(ErrInvalidTransform, "multiply has no factor")
+			return
+		}
+		var factor float64
+		if factor, err = operand(m.Multiply); err != nil {
+			return
+		}
+		f *= factor
+	case MathTypeClampMin, MathTypeClampMax, MathTypeClamp:
+		if (m.Type != MathTypeClampMax && m.ClampMin == nil) || (m.Type != MathTypeClampMin && m.ClampMax == nil) {
+			err = errors.Wrapf(ErrInvalidTransform, "%s has no limit", m.Type)
+			return
+		}
+		var limit float64
+		if m.ClampMin != nil && m.Type != MathTypeClampMax {
+			if limit, err = operand(m.ClampMin); err != nil {
+				return
+			}
+			f = math.Max(f, limit)
+		}
+		if m.ClampMax != nil && m.Type != MathTypeClampMin {
+			if limit, err = operand(m.ClampMax); err != nil {
+				return
+			}
+			f = math.Min(f, limit)
+		}
+	default:
+		err = errors.Wrapf(ErrInvalidTransform, "unknown math type %q", m.Type)
+		return
+	}
+
+	if math.IsInf(f, 0) {
+		err = errors.Wrapf(ErrInvalidInput, "%s overflows", m.Type)
+		return
+	}
+
+	if integer && f == math.Trunc(f) {
+		out = int64(f)
+		return
+	}
+	out = f
+	return
+}
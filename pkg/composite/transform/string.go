@@ -0,0 +1,329 @@
+package transform
+
+import (
+	"crypto/sha1" //nolint:gosec // Used for hashing values, not for security
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// StringType is the type of a string transform
+type StringType string
+
+// Supported string transform types
+const (
+	StringTypeFormat     StringType = "Format"
+	StringTypeConvert    StringType = "Convert"
+	StringTypeTrim       StringType = "Trim"
+	StringTypeTrimPrefix StringType = "TrimPrefix"
+	StringTypeTrimSuffix StringType = "TrimSuffix"
+	StringTypeRegexp     StringType = "Regexp"
+	StringTypeJoin       StringType = "Join"
+)
+
+// StringConversion is a conversion applied by a Convert string transform
+type StringConversion string
+
+// Supported string conversions
+const (
+	StringConversionToUpper    StringConversion = "ToUpper"
+	StringConversionToLower    StringConversion = "ToLower"
+	StringConversionToBase64   StringConversion = "ToBase64"
+	StringConversionFromBase64 StringConversion = "FromBase64"
+	StringConversionToJSON     StringConversion = "ToJson"
+	StringConversionToSHA1     StringConversion = "ToSha1"
+	StringConversionToSHA256   StringConversion = "ToSha256"
+	StringConversionToSHA512   StringConversion = "ToSha512"
+)
+
+// StringTransform applies a string operation to the value
+type StringTransform struct {
+	// Type of the operation
+	Type StringType `json:"type"`
+
+	// Format is a `fmt` format string receiving the value. Required for Format
+	Format *string `json:"fmt,omitempty"`
+
+	// Convert is the conversion to apply. Required for Convert
+	Convert *StringConversion `json:"convert,omitempty"`
+
+	// Trim is the cutset, prefix or suffix to remove. Required for Trim,
+	// TrimPrefix and TrimSuffix
+	Trim *string `json:"trim,omitempty"`
+
+	// Regexp extracts part of the value. Required for Regexp
+	Regexp *StringRegexp `json:"regexp,omitempty"`
+
+	// Join joins a list of values. Required for Join
+	Join *StringJoin `json:"join,omitempty"`
+}
+
+// StringRegexp extracts a match or capture group from the value
+type StringRegexp struct {
+	// Match is the regular expression
+	Match string `json:"match"`
+
+	// Group is the capture group to return. Defaults to the whole match
+	Group *int `json:"group,omitempty"`
+}
+
+// StringJoin joins the items of a list
+type StringJoin struct {
+	// Separator placed between the items
+	Separator string `json:"separator"`
+}
+
+// Format builds a transform formatting the value with a `fmt` format string
+func Format(format string) Transform {
+	return stringTransform(StringTransform{Type: StringTypeFormat, Format: &format})
+}
+
+// ToUpper builds a transform upper casing the value
+func ToUpper() Transform {
+	return convertString(StringConversionToUpper)
+}
+
+// ToLower builds a transform lower casing the value
+func ToLower() Transform {
+	return convertString(StringConversionToLower)
+}
+
+// ToBase64 builds a transform base64 encoding the value
+func ToBase64() Transform {
+	return convertString(StringConversionToBase64)
+}
+
+// FromBase64 builds a transform base64 decoding the value
+func FromBase64() Transform {
+	return convertString(StringConversionFromBase64)
+}
+
+// ToJSON builds a transform encoding the value as JSON
+func ToJSON() Transform {
+	return convertString(StringConversionToJSON)
+}
+
+// SHA1 builds a transform returning the hex encoded SHA-1 of the value
+func SHA1() Transform {
+	return convertString(StringConversionToSHA1)
+}
+
+// SHA256 builds a transform returning the hex encoded SHA-256 of the value
+func SHA256() Transform {
+	return convertString(StringConversionToSHA256)
+}
+
+// SHA512 builds a transform returning the hex encoded SHA-512 of the value
+func SHA512() Transform {
+	return convertString(StringConversionToSHA512)
+}
+
+// Trim builds a transform removing leading and trailing characters in cutset
+func Trim(cutset string) Transform {
+	return stringTransform(StringTransform{Type: StringTypeTrim, Trim: &cutset})
+}
+
+// TrimPrefix builds a transform removing prefix from the value
+func TrimPrefix(prefix string) Transform {
+	return stringTransform(StringTransform{Type: StringTypeTrimPrefix, Trim: &prefix})
+}
+
+// TrimSuffix builds a transform removing suffix from the value
+func TrimSuffix(suffix string) Transform {
+	return stringTransform(StringTransform{Type: StringTypeTrimSuffix, Trim: &suffix})
+}
+
+// Regexp builds a transform returning the capture group of expr
+//
+// Use group 0 for the whole match.
+func Regexp(expr string, group int) Transform {
+	return stringTransform(StringTransform{
+		Type: StringTypeRegexp,
+		Regexp: &StringRegexp{
+			Match: expr,
+			Group: &group,
+		},
+	})
+}
+
+// Join builds a transform joining a list of values with separator
+func Join(separator string) Transform {
+	return stringTransform(StringTransform{
+		Type: StringTypeJoin,
+		Join: &StringJoin{
+			Separator: separator,
+		},
+	})
+}
+
+// stringTransform wraps s in a Transform
+func stringTransform(s StringTransform) Transform {
+	return Transform{
+		Type:   TypeString,
+		String: &s,
+	}
+}
+
+// convertString builds a Convert string transform
+func convertString(c StringConversion) Transform {
+	return stringTransform(StringTransform{Type: StringTypeConvert, Convert: &c})
+}
+
+// resolve applies the operation to v
+func (s *StringTransform) resolve(v any) (out any, err error) {
+	switch s.Type {
+	case StringTypeFormat:
+		if s.Format == nil {
+			break
+		}
+		return fmt.Sprintf(*s.Format, v), nil
+	case StringTypeConvert:
+		if s.Convert == nil {
+			break
+		}
+		return s.conversion(v)
+	case StringTypeJoin:
+		if s.Join == nil {
+			break
+		}
+		return join(v, s.Join.Separator)
+	case StringTypeTrim, StringTypeTrimPrefix, StringTypeTrimSuffix, StringTypeRegexp:
+		var in string
+		if in, err = toString(v); err != nil {
+			return
+		}
+		return s.resolveString(in)
+	default:
+		err = errors.Wrapf(ErrInvalidTransform, "unknown string type %q", s.Type)
+		return
+	}
+
+	err = errors.Wrapf(ErrInvalidTransform, "%s string transform is not configured", s.Type)
+	return
+}
+
+// resolveString applies operations working on a single string
+func (s *StringTransform) resolveString(in string) (out any, err error) {
+	switch s.Type {
+	case StringTypeRegexp:
+		if s.Regexp == nil {
+			break
+		}
+		return extract(in, s.Regexp)
+	default:
+		if s.Trim == nil {
+			break
+		}
+
+		switch s.Type {
+		case StringTypeTrimPrefix:
+			return strings.TrimPrefix(in, *s.Trim), nil
+		case StringTypeTrimSuffix:
+			return strings.TrimSuffix(in, *s.Trim), nil
+		}
+		return strings.Trim(in, *s.Trim), nil
+	}
+
+	err = errors.Wrapf(ErrInvalidTransform, "%s string transform is not configured", s.Type)
+	return
+}
+
+// conversion applies the configured string conversion to v
+func (s *StringTransform) conversion(v any) (out any, err error) {
+	if *s.Convert == StringConversionToJSON {
+		var b []byte
+		if b, err = json.Marshal(v); err != nil {
+			err = errors.Wrapf(ErrInvalidInput, "cannot encode %T: %s", v, err)
+			return
+		}
+		return string(b), nil
+	}
+
+	var in string
+	if in, err = toString(v); err != nil {
+		return
+	}
+
+	switch *s.Convert {
+	case StringConversionToUpper:
+		out = strings.ToUpper(in)
+	case StringConversionToLower:
+		out = strings.ToLower(in)
+	case StringConversionToBase64:
+		out = base64.StdEncoding.EncodeToString([]byte(in))
+	case StringConversionFromBase64:
+		var b []byte
+		if b, err = base64.StdEncoding.DecodeString(in); err != nil {
+			err = errors.Wrapf(ErrInvalidInput, "cannot decode base64: %s", err)
+			return
+		}
+		out = string(b)
+	case StringConversionToSHA1:
+		sum := sha1.Sum([]byte(in)) //nolint:gosec // Used for hashing values, not for security
+		out = hex.EncodeToString(sum[:])
+	case StringConversionToSHA256:
+		sum := sha256.Sum256([]byte(in))
+		out = hex.EncodeToString(sum[:])
+	case StringConversionToSHA512:
+		sum := sha512.Sum512([]byte(in))
+		out = hex.EncodeToString(sum[:])
+	default:
+		err = errors.Wrapf(ErrInvalidTransform, "unknown string conversion %q", *s.Convert)
+	}
+	return
+}
+
+// extract returns the configured capture group of r in s
+func extract(s string, r *StringRegexp) (out any, err error) {
+	var re *regexp.Regexp
+	if re, err = regexp.Compile(r.Match); err != nil {
+		err = errors.Wrapf(ErrInvalidTransform, "cannot compile %q: %s", r.Match, err)
+		return
+	}
+
+	groups := re.FindStringSubmatch(s)
+	if groups == nil {
+		err = errors.Wrapf(ErrNoMatch, "%q does not match %q", s, r.Match)
+		return
+	}
+
+	group := 0
+	if r.Group != nil {
+		group = *r.Group
+	}
+
+	if group < 0 || group >= len(groups) {
+		err = errors.Wrapf(ErrInvalidTransform, "%q has no capture group %d", r.Match, group)
+		return
+	}
+	out = groups[group]
+	return
+}
+
+// join joins the items of the list v with separator
+func join(v any, separator string) (out any, err error) {
+	list, ok := v.([]any)
+	if !ok {
+		if strs, ok := v.([]string); ok {
+			return strings.Join(strs, separator), nil
+		}
+		err = errors.Wrapf(ErrInvalidInput, "%T is not a list", v)
+		return
+	}
+
+	items := make([]string, len(list))
+	for i, item := range list {
+		if items[i], err = toString(item); err != nil {
+			return
+		}
+	}
+	out = strings.Join(items, separator)
+	return
+}
@@ -0,0 +1,165 @@
+// Package transform provides value transforms for use with composition
+// functions.
+//
+// Transforms work on the `any` values found in unstructured objects and can be
+// chained with `Apply`. Each transform is a plain struct carrying json tags so
+// that transforms can either be built in Go with the constructors in this
+// package or loaded from the function Input. Free-form values are held as
+// `apiextensionsv1.JSON` and the types implement `DeepCopy` so they can be
+// embedded in an Input generated with controller-gen.
+//
+// Example:
+//
+//	v, err := transform.Apply(value,
+//		transform.Map(map[string]any{"small": "t3.small"}),
+//		transform.ToUpper(),
+//	)
+//
+// +kubebuilder:object:generate=true
+package transform
+
+import (
+	"encoding/json"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// Type is the type of a transform
+type Type string
+
+// Supported transform types
+const (
+	TypeMap     Type = "map"
+	TypeMatch   Type = "match"
+	TypeMath    Type = "math"
+	TypeString  Type = "string"
+	TypeConvert Type = "convert"
+)
+
+// Transform changes a single value
+type Transform struct {
+	// Type of the transform
+	Type Type `json:"type"`
+
+	// Map looks the value up in a map. Required for map transforms
+	// +optional
+	Map map[string]extv1.JSON `json:"map,omitempty"`
+
+	// Match matches the value against a list of patterns. Required for match
+	// transforms
+	Match *MatchTransform `json:"match,omitempty"`
+
+	// Math applies a mathematical operation. Required for math transforms
+	Math *MathTransform `json:"math,omitempty"`
+
+	// String applies a string operation. Required for string transforms
+	String *StringTransform `json:"string,omitempty"`
+
+	// Convert converts the value to another type. Required for convert
+	// transforms
+	Convert *ConvertTransform `json:"convert,omitempty"`
+}
+
+// Apply runs the transforms against v in order
+//
+// An `Error` holding the index of the failing transform is returned when a
+// transform fails.
+func Apply(v any, transforms ...Transform) (out any, err error) {
+	out = v
+	for i, t := range transforms {
+		if out, err = t.Apply(out); err != nil {
+			out, err = nil, &Error{Index: i, Type: t.Type, Err: err}
+			return
+		}
+	}
+	return
+}
+
+// Apply runs the transform against v
+func (t Transform) Apply(v any) (out any, err error) {
+	switch t.Type {
+	case TypeMap:
+		return resolveMap(t.Map, v)
+	case TypeMatch:
+		if t.Match == nil {
+			break
+		}
+		return t.Match.resolve(v)
+	case TypeMath:
+		if t.Math == nil {
+			break
+		}
+		return t.Math.resolve(v)
+	case TypeString:
+		if t.String == nil {
+			break
+		}
+		return t.String.resolve(v)
+	case TypeConvert:
+		if t.Convert == nil {
+			break
+		}
+		return t.Convert.resolve(v)
+	default:
+		err = errors.Wrapf(ErrInvalidTransform, "unknown type %q", t.Type)
+		return
+	}
+
+	err = errors.Wrapf(ErrInvalidTransform, "%s transform is not configured", t.Type)
+	return
+}
+
+// Map builds a transform that looks the value up in m
+//
+// The value is converted to a string before the lookup. An `ErrNotFound` error
+// is returned when the key does not exist. The values of `m` must be
+// serialisable to JSON.
+func Map(m map[string]any) Transform {
+	values := make(map[string]extv1.JSON, len(m))
+	for k, v := range m {
+		values[k] = toJSON(v)
+	}
+
+	return Transform{
+		Type: TypeMap,
+		Map:  values,
+	}
+}
+
+// resolveMap looks v up in m
+func resolveMap(m map[string]extv1.JSON, v any) (out any, err error) {
+	var key string
+	if key, err = toString(v); err != nil {
+		return
+	}
+
+	value, ok := m[key]
+	if !ok {
+		err = errors.Wrapf(ErrNotFound, "%q", key)
+		return
+	}
+	return fromJSON(value)
+}
+
+// toJSON serialises v for use in a transform. Values that cannot be
+// serialised become null
+func toJSON(v any) extv1.JSON {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return extv1.JSON{}
+	}
+	return extv1.JSON{Raw: b}
+}
+
+// fromJSON decodes a value held by a transform. An empty value decodes to nil
+func fromJSON(j extv1.JSON) (out any, err error) {
+	if len(j.Raw) == 0 {
+		return
+	}
+
+	if err = json.Unmarshal(j.Raw, &out); err != nil {
+		err = errors.Wrapf(ErrInvalidTransform, "cannot decode %s: %s", j.Raw, err)
+	}
+	return
+}
@@ -0,0 +1,220 @@
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	cases := map[string]struct {
+		value      any
+		transforms []Transform
+		want       any
+		wantErr    error
+		wantIndex  int
+	}{
+		"NoTransforms": {
+			value: "unchanged",
+			want:  "unchanged",
+		},
+		"Map": {
+			value:      "small",
+			transforms: []Transform{Map(map[string]any{"small": "t3.small", "large": "t3.large"})},
+			want:       "t3.small",
+		},
+		"MapObject": {
+			value:      "eu",
+			transforms: []Transform{Map(map[string]any{"eu": map[string]any{"zones": 3}})},
+			want:       map[string]any{"zones": float64(3)},
+		},
+		"MapNotFound": {
+			value:      "medium",
+			transforms: []Transform{Map(map[string]any{"small": "t3.small"})},
+			wantErr:    ErrNotFound,
+		},
+		"MatchLiteral": {
+			value:      "prod",
+			transforms: []Transform{Match(MatchLiteral("dev", 1), MatchLiteral("prod", 3))},
+			want:       float64(3),
+		},
+		"MatchRegexp": {
+			value:      "eu-west-1",
+			transforms: []Transform{Match(MatchRegexp(`^eu-`, "europe"))},
+			want:       "europe",
+		},
+		"MatchFallback": {
+			value:      "us-east-1",
+			transforms: []Transform{MatchWithFallback("other", MatchRegexp(`^eu-`, "europe"))},
+			want:       "other",
+		},
+		"MatchNoMatch": {
+			value:      "us-east-1",
+			transforms: []Transform{Match(MatchRegexp(`^eu-`, "europe"))},
+			wantErr:    ErrNoMatch,
+		},
+		"MatchInvalidRegexp": {
+			value:      "a",
+			transforms: []Transform{Match(MatchRegexp(`(`, "b"))},
+			wantErr:    ErrInvalidTransform,
+		},
+		"MathMultiplyAndClamp": {
+			value:      int64(3),
+			transforms: []Transform{Multiply(10), Clamp(0, 20)},
+			want:       int64(20),
+		},
+		"MathMultiplyTinyFactor": {
+			value:      int64(10),
+			transforms: []Transform{Multiply(1e-12)},
+			want:       1e-11,
+		},
+		"MathClampSubNano": {
+			value:      0.0,
+			transforms: []Transform{ClampMin(1e-15)},
+			want:       1e-15,
+		},
+		"MathNaNValue": {
+			value:      math.NaN(),
+			transforms: []Transform{Multiply(2)},
+			wantErr:    ErrInvalidInput,
+		},
+		"MathNaNFactor": {
+			value:      int64(1),
+			transforms: []Transform{Clamp(0, 2), Multiply(math.NaN())},
+			wantErr:    ErrInvalidInput,
+			wantIndex:  1,
+		},
+		"MathInfiniteLimit": {
+			value:      int64(1),
+			transforms: []Transform{ClampMax(math.Inf(1))},
+			wantErr:    ErrInvalidInput,
+		},
+		"MathOverflow": {
+			value:      1e300,
+			transforms: []Transform{Multiply(1e300)},
+			wantErr:    ErrInvalidInput,
+		},
+		"StringChain": {
+			value:      " Cluster ",
+			transforms: []Transform{Trim(" "), ToLower(), Format("%s-a")},
+			want:       "cluster-a",
+		},
+		"StringRegexpGroup": {
+			value:      "arn:aws:iam::123456789012:role/admin",
+			transforms: []Transform{Regexp(`::(\d+):`, 1)},
+			want:       "123456789012",
+		},
+		"StringJoin": {
+			value:      []any{"a", "b"},
+			transforms: []Transform{Join(",")},
+			want:       "a,b",
+		},
+		"Base64RoundTrip": {
+			value:      "secret",
+			transforms: []Transform{ToBase64(), FromBase64()},
+			want:       "secret",
+		},
+		"ConvertQuantity": {
+			value:      "500m",
+			transforms: []Transform{ConvertWithFormat(ConvertTypeFloat64, ConvertFormatQuantity)},
+			want:       0.5,
+		},
+		"ConvertInvalidInput": {
+			value:      "not a number",
+			transforms: []Transform{Convert(ConvertTypeInt)},
+			wantErr:    ErrInvalidInput,
+		},
+		"ErrorHoldsIndex": {
+			value:      "small",
+			transforms: []Transform{ToUpper(), Map(map[string]any{"small": "t3.small"})},
+			wantErr:    ErrNotFound,
+			wantIndex:  1,
+		},
+		"UnknownType": {
+			value:      "a",
+			transforms: []Transform{{Type: "unknown"}},
+			wantErr:    ErrInvalidTransform,
+		},
+		"NotConfigured": {
+			value:      "a",
+			transforms: []Transform{{Type: TypeString}},
+			wantErr:    ErrInvalidTransform,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Apply(tc.value, tc.transforms...)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("Apply(...): error = %v, want %v", err, tc.wantErr)
+				}
+
+				var terr *Error
+				if !errors.As(err, &terr) || terr.Index != tc.wantIndex {
+					t.Errorf("Apply(...): error = %#v, want index %d", err, tc.wantIndex)
+				}
+
+				if got != nil {
+					t.Errorf("Apply(...) = %v, want nil on error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Apply(...): unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Apply(...) = %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTransformFromInput(t *testing.T) {
+	input := `[
+		{"type": "map", "map": {"small": "t3.small", "large": {"size": "t3.large"}}},
+		{"type": "match", "match": {
+			"patterns": [{"type": "literal", "literal": "t3.small", "result": 1}],
+			"fallbackValue": null,
+			"fallbackTo": "Input"
+		}},
+		{"type": "math", "math": {"type": "Multiply", "multiply": "1.5"}},
+		{"type": "math", "math": {"type": "ClampMax", "clampMax": 2}},
+		{"type": "math", "math": {"type": "ClampMin", "clampMin": "500m"}},
+		{"type": "convert", "convert": {"toType": "string"}}
+	]`
+
+	var transforms []Transform
+	if err := json.Unmarshal([]byte(input), &transforms); err != nil {
+		t.Fatalf("cannot unmarshal transforms: %v", err)
+	}
+
+	got, err := Apply("small", transforms...)
+	if err != nil {
+		t.Fatalf("Apply(...): unexpected error: %v", err)
+	}
+
+	if got != "1.5" {
+		t.Errorf("Apply(...) = %#v, want %q", got, "1.5")
+	}
+}
+
+func TestTransformDeepCopy(t *testing.T) {
+	in := MatchWithFallback("other", MatchLiteral("a", "b"))
+	out := in.DeepCopy()
+
+	out.Match.Patterns[0].Result.Raw[1] = 'x'
+	out.Match.FallbackValue.Raw = []byte(`"changed"`)
+
+	if got, _ := Apply("a", in); got != "b" {
+		t.Errorf("Apply(...) = %#v after changing the copy, want %q", got, "b")
+	}
+
+	if got, _ := Apply("z", in); got != "other" {
+		t.Errorf("Apply(...) = %#v after changing the copy, want %q", got, "other")
+	}
+}
@@ -0,0 +1,229 @@
+//go:build !ignore_autogenerated
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package transform
+
+import (
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConvertTransform) DeepCopyInto(out *ConvertTransform) {
+	*out = *in
+	if in.Format != nil {
+		in, out := &in.Format, &out.Format
+		*out = new(ConvertFormat)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConvertTransform.
+func (in *ConvertTransform) DeepCopy() *ConvertTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(ConvertTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatchPattern) DeepCopyInto(out *MatchPattern) {
+	*out = *in
+	if in.Literal != nil {
+		in, out := &in.Literal, &out.Literal
+		*out = new(string)
+		**out = **in
+	}
+	if in.Regexp != nil {
+		in, out := &in.Regexp, &out.Regexp
+		*out = new(string)
+		**out = **in
+	}
+	in.Result.DeepCopyInto(&out.Result)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchPattern.
+func (in *MatchPattern) DeepCopy() *MatchPattern {
+	if in == nil {
+		return nil
+	}
+	out := new(MatchPattern)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatchTransform) DeepCopyInto(out *MatchTransform) {
+	*out = *in
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]MatchPattern, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FallbackValue != nil {
+		in, out := &in.FallbackValue, &out.FallbackValue
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchTransform.
+func (in *MatchTransform) DeepCopy() *MatchTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(MatchTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MathTransform) DeepCopyInto(out *MathTransform) {
+	*out = *in
+	if in.Multiply != nil {
+		in, out := &in.Multiply, &out.Multiply
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClampMin != nil {
+		in, out := &in.ClampMin, &out.ClampMin
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClampMax != nil {
+		in, out := &in.ClampMax, &out.ClampMax
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MathTransform.
+func (in *MathTransform) DeepCopy() *MathTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(MathTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StringJoin) DeepCopyInto(out *StringJoin) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringJoin.
+func (in *StringJoin) DeepCopy() *StringJoin {
+	if in == nil {
+		return nil
+	}
+	out := new(StringJoin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StringRegexp) DeepCopyInto(out *StringRegexp) {
+	*out = *in
+	if in.Group != nil {
+		in, out := &in.Group, &out.Group
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringRegexp.
+func (in *StringRegexp) DeepCopy() *StringRegexp {
+	if in == nil {
+		return nil
+	}
+	out := new(StringRegexp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StringTransform) DeepCopyInto(out *StringTransform) {
+	*out = *in
+	if in.Format != nil {
+		in, out := &in.Format, &out.Format
+		*out = new(string)
+		**out = **in
+	}
+	if in.Convert != nil {
+		in, out := &in.Convert, &out.Convert
+		*out = new(StringConversion)
+		**out = **in
+	}
+	if in.Trim != nil {
+		in, out := &in.Trim, &out.Trim
+		*out = new(string)
+		**out = **in
+	}
+	if in.Regexp != nil {
+		in, out := &in.Regexp, &out.Regexp
+		*out = new(StringRegexp)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Join != nil {
+		in, out := &in.Join, &out.Join
+		*out = new(StringJoin)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringTransform.
+func (in *StringTransform) DeepCopy() *StringTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(StringTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Transform) DeepCopyInto(out *Transform) {
+	*out = *in
+	if in.Map != nil {
+		in, out := &in.Map, &out.Map
+		*out = make(map[string]v1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = new(MatchTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Math != nil {
+		in, out := &in.Math, &out.Math
+		*out = new(MathTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.String != nil {
+		in, out := &in.String, &out.String
+		*out = new(StringTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Convert != nil {
+		in, out := &in.Convert, &out.Convert
+		*out = new(ConvertTransform)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Transform.
+func (in *Transform) DeepCopy() *Transform {
+	if in == nil {
+		return nil
+	}
+	out := new(Transform)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,113 @@
+package composite
+
+import (
+	"slices"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"github.com/crossplane/function-sdk-go/resource"
+	"google.golang.org/protobuf/types/known/durationpb"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TTLState summarises the composition for a TTLPolicy
+type TTLState struct {
+	// NotReady holds the pipeline names of desired composed resources that
+	// are not ready
+	NotReady []resource.Name
+
+	// Gated holds the pipeline names of desired composed resources held back
+	// because their dependencies are not satisfied
+	Gated []resource.Name
+
+	// Throttled is true when `MarkThrottled` was called during this run
+	Throttled bool
+}
+
+// TTLPolicy decides the TTL of the response from the state of the composition
+//
+// Return false to leave the TTL on the response untouched.
+type TTLPolicy func(state TTLState) (ttl time.Duration, ok bool)
+
+// AdaptiveTTL requeues quickly while the composition is settling and slowly
+// once it is stable
+//
+//   - `stable` The TTL when all desired composed resources are ready
+//   - `unstable` The TTL when any desired composed resource is not ready or
+//     is waiting for its dependencies
+//   - `throttled` The TTL when an upstream API throttled the function
+func AdaptiveTTL(stable, unstable, throttled time.Duration) TTLPolicy {
+	return func(state TTLState) (time.Duration, bool) {
+		switch {
+		case state.Throttled:
+			return throttled, true
+		case len(state.NotReady) > 0, len(state.Gated) > 0:
+			return unstable, true
+		}
+		return stable, true
+	}
+}
+
+// SetTTLPolicy sets the policy `ToResponse` uses to decide the response TTL
+//
+// Without a policy the TTL given to `response.To` is kept.
+func (c *TypedComposition[XR, In]) SetTTLPolicy(p TTLPolicy) {
+	c.ttlPolicy = p
+}
+
+// MarkThrottled records that an upstream API throttled the function during
+// this run
+func (c *TypedComposition[XR, In]) MarkThrottled() {
+	c.throttled = true
+}
+
+// setTTL applies the TTL policy to the response
+func (c *TypedComposition[XR, In]) setTTL(r *fnv1.RunFunctionResponse) {
+	if c.ttlPolicy == nil {
+		return
+	}
+
+	state := TTLState{
+		Throttled: c.throttled,
+	}
+	for n, d := range c.DesiredComposed {
+		if !c.isReady(n, d) {
+			state.NotReady = append(state.NotReady, n)
+		}
+	}
+	slices.Sort(state.NotReady)
+
+	for _, g := range c.gated {
+		state.Gated = append(state.Gated, g.Name)
+	}
+
+	ttl, ok := c.ttlPolicy(state)
+	if !ok {
+		return
+	}
+
+	if r.Meta == nil {
+		r.Meta = &fnv1.ResponseMeta{}
+	}
+	r.Meta.Ttl = durationpb.New(ttl)
+}
+
+// isReady returns true if the desired composed resource is ready
+//
+// Where readiness is left to Crossplane, the ready condition of the observed
+// resource is used.
+func (c *TypedComposition[XR, In]) isReady(n resource.Name, d *resource.DesiredComposed) bool {
+	switch d.Ready {
+	case resource.ReadyTrue:
+		return true
+	case resource.ReadyFalse:
+		return false
+	}
+
+	o, ok := c.ObservedComposed[n]
+	if !ok {
+		return false
+	}
+	return o.Resource.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue
+}
@@ -0,0 +1,127 @@
+package composite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/function-sdk-go/response"
+)
+
+func TestAdaptiveTTL(t *testing.T) {
+	notReady := `{
+		"apiVersion": "s3.aws.upbound.io/v1beta1",
+		"kind": "Bucket",
+		"metadata": {"name": "bucket"},
+		"status": {"conditions": [{"type": "Ready", "status": "False"}]}
+	}`
+	ready := `{
+		"apiVersion": "s3.aws.upbound.io/v1beta1",
+		"kind": "Bucket",
+		"metadata": {"name": "bucket"},
+		"status": {"conditions": [{"type": "Ready", "status": "True"}]}
+	}`
+
+	cases := map[string]struct {
+		observed  map[string]string
+		opts      []DesiredOption
+		throttled bool
+		policy    TTLPolicy
+		want      time.Duration
+	}{
+		"NoPolicy": {
+			opts: []DesiredOption{WithReadiness(ReadyFromObserved())},
+			want: response.DefaultTTL,
+		},
+		"Stable": {
+			policy: AdaptiveTTL(time.Hour, time.Minute, 5*time.Minute),
+			want:   time.Hour,
+		},
+		"NotReady": {
+			observed: map[string]string{"r": notReady},
+			opts:     []DesiredOption{WithReadiness(ReadyFromObserved())},
+			policy:   AdaptiveTTL(time.Hour, time.Minute, 5*time.Minute),
+			want:     time.Minute,
+		},
+		"UnspecifiedUsesObserved": {
+			observed: map[string]string{"r": ready},
+			opts:     []DesiredOption{WithReadiness(ReadyUnspecified())},
+			policy:   AdaptiveTTL(time.Hour, time.Minute, 5*time.Minute),
+			want:     time.Hour,
+		},
+		"UnspecifiedNotObserved": {
+			opts:   []DesiredOption{WithReadiness(ReadyUnspecified())},
+			policy: AdaptiveTTL(time.Hour, time.Minute, 5*time.Minute),
+			want:   time.Minute,
+		},
+		"Gated": {
+			opts:   []DesiredOption{DependsOnReady("missing")},
+			policy: AdaptiveTTL(time.Hour, time.Minute, 5*time.Minute),
+			want:   time.Minute,
+		},
+		"Throttled": {
+			observed:  map[string]string{"r": notReady},
+			opts:      []DesiredOption{WithReadiness(ReadyFromObserved())},
+			throttled: true,
+			policy:    AdaptiveTTL(time.Hour, time.Minute, 5*time.Minute),
+			want:      5 * time.Minute,
+		},
+		"PolicyDeclines": {
+			policy: func(TTLState) (time.Duration, bool) { return time.Second, false },
+			want:   response.DefaultTTL,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := testRequest(t, "", tc.observed, nil)
+			c := newTestComposition(t, req)
+			if err := c.AddDesired("r", testObject(t, testDesiredBucket), tc.opts...); err != nil {
+				t.Fatalf("AddDesired(...): unexpected error: %v", err)
+			}
+			if tc.throttled {
+				c.MarkThrottled()
+			}
+			if tc.policy != nil {
+				c.SetTTLPolicy(tc.policy)
+			}
+
+			rsp := response.To(req, response.DefaultTTL)
+			if err := c.ToResponse(rsp); err != nil {
+				t.Fatalf("ToResponse(...): unexpected error: %v", err)
+			}
+
+			if got := rsp.GetMeta().GetTtl().AsDuration(); got != tc.want {
+				t.Errorf("ToResponse(...): ttl = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTTLState(t *testing.T) {
+	req := testRequest(t, "", nil, nil)
+	c := newTestComposition(t, req)
+	for _, n := range []string{"b", "a"} {
+		if err := c.AddDesired(n, testObject(t, testDesiredBucket), WithReadiness(ReadyFromObserved())); err != nil {
+			t.Fatalf("AddDesired(...): unexpected error: %v", err)
+		}
+	}
+	if err := c.AddDesired("c", testObject(t, testDesiredBucket), DependsOnReady("missing")); err != nil {
+		t.Fatalf("AddDesired(...): unexpected error: %v", err)
+	}
+
+	var got TTLState
+	c.SetTTLPolicy(func(state TTLState) (time.Duration, bool) {
+		got = state
+		return 0, false
+	})
+	if err := c.ToResponse(response.To(req, response.DefaultTTL)); err != nil {
+		t.Fatalf("ToResponse(...): unexpected error: %v", err)
+	}
+
+	if len(got.NotReady) != 2 || got.NotReady[0] != "a" || got.NotReady[1] != "b" {
+		t.Errorf("TTLState.NotReady = %v, want [a b]", got.NotReady)
+	}
+	if len(got.Gated) != 1 || got.Gated[0] != "c" {
+		t.Errorf("TTLState.Gated = %v, want [c]", got.Gated)
+	}
+}
@@ -0,0 +1,182 @@
+package composite
+
+import (
+	"slices"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/function-sdk-go/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// UsageAPIVersion is the default API version of the Usage objects generated
+// for `Uses`
+const UsageAPIVersion = "apiextensions.crossplane.io/v1alpha1"
+
+// UsageOption configures a Usage declared with `Uses`
+type UsageOption func(*usage)
+
+type usage struct {
+	by, of         string
+	apiVersion     string
+	reason         string
+	replayDeletion bool
+}
+
+// WithReplayDeletion makes Crossplane replay the deletion of the used resource
+// once the resource using it is gone instead of waiting for the next
+// reconciliation
+func WithReplayDeletion() UsageOption {
+	return func(u *usage) {
+		u.replayDeletion = true
+	}
+}
+
+// WithUsageReason sets the reason of the Usage, shown when the deletion of the
+// used resource is blocked
+func WithUsageReason(reason string) UsageOption {
+	return func(u *usage) {
+		u.reason = reason
+	}
+}
+
+// WithUsageAPIVersion sets the API version of the generated Usage. Defaults to
+// `UsageAPIVersion`
+func WithUsageAPIVersion(apiVersion string) UsageOption {
+	return func(u *usage) {
+		u.apiVersion = apiVersion
+	}
+}
+
+// Uses declares that the composed resource `a` uses the composed resource `b`
+//
+// Crossplane blocks the deletion of `b` for as long as `a` exists. A Usage
+// object is added to the desired resources by `ToResponse` once both resources
+// are desired and their names are known. The Usage is kept while both
+// resources are still observed, even after they are no longer desired, and is
+// pruned once either side is gone.
+//
+// The Usage is named with `ResourceName` from the composite name and both
+// pipeline names. Declaring the same pair twice replaces the earlier
+// declaration.
+//
+//   - `a` The pipeline name of the resource using `b`
+//   - `b` The pipeline name of the resource being used
+//   - `opts` Options such as `WithReplayDeletion` or `WithUsageReason`
+//
+// Example:
+//
+//	// Keep the role until the cluster using it has been deleted
+//	composed.Uses("cluster", "role", composite.WithReplayDeletion())
+func (c *TypedComposition[XR, In]) Uses(a, b string, opts ...UsageOption) {
+	u := usage{
+		by:         a,
+		of:         b,
+		apiVersion: UsageAPIVersion,
+	}
+	for _, opt := range opts {
+		opt(&u)
+	}
+
+	for i := range c.usages {
+		if c.usages[i].by == a && c.usages[i].of == b {
+			c.usages[i] = u
+			return
+		}
+	}
+	c.usages = append(c.usages, u)
+}
+
+// UsageName returns the pipeline name of the Usage generated for `a` using
+// `b`
+func UsageName(a, b string) string {
+	return PipelineName(a, "uses", b)
+}
+
+// declaresUsage returns true if `n` is the pipeline name of a declared Usage
+func (c *TypedComposition[XR, In]) declaresUsage(n resource.Name) bool {
+	return slices.ContainsFunc(c.usages, func(u usage) bool {
+		return UsageName(u.by, u.of) == string(n)
+	})
+}
+
+// setUsages adds the declared Usages to the desired composed resources and
+// prunes those whose resources are gone
+//
+// A resource that is no longer desired is still referenced while it is
+// observed, so the Usage keeps blocking the deletion of the used resource until
+// the resource using it has actually been deleted.
+func (c *TypedComposition[XR, In]) setUsages() (err error) {
+	for _, u := range c.usages {
+		n := UsageName(u.by, u.of)
+
+		by, byOk := c.usageRef(u.by)
+		of, ofOk := c.usageRef(u.of)
+		if !byOk || !ofOk {
+			c.RemoveDesired(n)
+			continue
+		}
+
+		spec := map[string]any{
+			"of": of,
+			"by": by,
+		}
+		if u.replayDeletion {
+			spec["replayDeletion"] = true
+		}
+		if u.reason != "" {
+			spec["reason"] = u.reason
+		}
+
+		object := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": u.apiVersion,
+			"kind":       "Usage",
+			"metadata": map[string]any{
+				"name": c.ResourceName(u.by, "uses", u.of),
+			},
+			"spec": spec,
+		}}
+
+		if err = c.AddDesired(n, object); err != nil {
+			err = errors.Wrapf(err, "cannot add usage of %q by %q", u.of, u.by)
+			return
+		}
+	}
+	return
+}
+
+// usageRef returns the reference to the composed resource `n` used in a
+// Usage. `ok` is false while `n` is neither desired nor observed or its name is
+// not known yet
+func (c *TypedComposition[XR, In]) usageRef(n string) (ref map[string]any, ok bool) {
+	var apiVersion, kind, name string
+
+	if desired, found := c.DesiredComposed[resource.Name(n)]; found {
+		apiVersion = desired.Resource.GetAPIVersion()
+		kind = desired.Resource.GetKind()
+		name = desired.Resource.GetName()
+	}
+
+	if observed, found := c.ObservedComposed[resource.Name(n)]; found {
+		if apiVersion == "" {
+			apiVersion = observed.Resource.GetAPIVersion()
+			kind = observed.Resource.GetKind()
+		}
+		if observed.Resource.GetName() != "" {
+			name = observed.Resource.GetName()
+		}
+	}
+
+	if name == "" {
+		return
+	}
+
+	ref = map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"resourceRef": map[string]any{
+			"name": name,
+		},
+	}
+	ok = true
+	return
+}
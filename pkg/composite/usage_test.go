@@ -0,0 +1,137 @@
+package composite
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	fnv1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"github.com/crossplane/function-sdk-go/resource"
+)
+
+const (
+	testCluster = `{
+		"apiVersion": "eks.aws.upbound.io/v1beta1",
+		"kind": "Cluster",
+		"metadata": {"name": "xr-cluster"}
+	}`
+
+	testRole = `{
+		"apiVersion": "iam.aws.upbound.io/v1beta1",
+		"kind": "Role",
+		"metadata": {"name": "xr-role"}
+	}`
+)
+
+func TestUsages(t *testing.T) {
+	usage := UsageName("cluster", "role")
+
+	cases := map[string]struct {
+		observed map[string]string
+		add      map[string]string
+		want     map[string]any
+	}{
+		"BothDesired": {
+			add: map[string]string{"cluster": testCluster, "role": testRole},
+			want: map[string]any{
+				"by": map[string]any{
+					"apiVersion":  "eks.aws.upbound.io/v1beta1",
+					"kind":        "Cluster",
+					"resourceRef": map[string]any{"name": "xr-cluster"},
+				},
+				"of": map[string]any{
+					"apiVersion":  "iam.aws.upbound.io/v1beta1",
+					"kind":        "Role",
+					"resourceRef": map[string]any{"name": "xr-role"},
+				},
+				"replayDeletion": true,
+			},
+		},
+		"NameNotKnown": {
+			add: map[string]string{
+				"cluster": `{"apiVersion": "eks.aws.upbound.io/v1beta1", "kind": "Cluster"}`,
+				"role":    testRole,
+			},
+		},
+		"ByStillObserved": {
+			observed: map[string]string{"cluster": testCluster, "role": testRole},
+			add:      map[string]string{"role": testRole},
+			want: map[string]any{
+				"by": map[string]any{
+					"apiVersion":  "eks.aws.upbound.io/v1beta1",
+					"kind":        "Cluster",
+					"resourceRef": map[string]any{"name": "xr-cluster"},
+				},
+				"of": map[string]any{
+					"apiVersion":  "iam.aws.upbound.io/v1beta1",
+					"kind":        "Role",
+					"resourceRef": map[string]any{"name": "xr-role"},
+				},
+				"replayDeletion": true,
+			},
+		},
+		"BothStillObserved": {
+			observed: map[string]string{"cluster": testCluster, "role": testRole},
+			want: map[string]any{
+				"by": map[string]any{
+					"apiVersion":  "eks.aws.upbound.io/v1beta1",
+					"kind":        "Cluster",
+					"resourceRef": map[string]any{"name": "xr-cluster"},
+				},
+				"of": map[string]any{
+					"apiVersion":  "iam.aws.upbound.io/v1beta1",
+					"kind":        "Role",
+					"resourceRef": map[string]any{"name": "xr-role"},
+				},
+				"replayDeletion": true,
+			},
+		},
+		"ByGone": {
+			observed: map[string]string{"role": testRole},
+			add:      map[string]string{"role": testRole},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			observed := make(map[string]string, len(tc.observed)+1)
+			for n, o := range tc.observed {
+				observed[n] = o
+			}
+			// The Usage itself exists from an earlier run
+			observed[usage] = `{"apiVersion": "apiextensions.crossplane.io/v1alpha1", "kind": "Usage", "metadata": {"name": "u"}}`
+
+			c := newTestComposition(t, testRequest(t, "", observed, nil))
+			for n, o := range tc.add {
+				if err := c.AddDesired(n, testObject(t, o)); err != nil {
+					t.Fatalf("AddDesired(%q, ...): unexpected error: %v", n, err)
+				}
+			}
+			c.Uses("cluster", "role", WithReplayDeletion())
+
+			if pruned := c.Prune(); slices.Contains(pruned.Stale, resource.Name(usage)) {
+				t.Errorf("Prune(...) = %v, want the declared usage to be left to ToResponse", pruned)
+			}
+
+			rsp := &fnv1.RunFunctionResponse{}
+			if err := c.ToResponse(rsp); err != nil {
+				t.Fatalf("ToResponse(...): unexpected error: %v", err)
+			}
+
+			r, ok := rsp.GetDesired().GetResources()[usage]
+			if tc.want == nil {
+				if ok {
+					t.Errorf("ToResponse(...): want usage %q to be pruned, got %v", usage, r.GetResource().AsMap())
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("ToResponse(...): usage %q is not desired", usage)
+			}
+
+			if got := r.GetResource().AsMap()["spec"]; !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ToResponse(...): usage spec = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}